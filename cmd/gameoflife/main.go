@@ -1,13 +1,71 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"math/rand/v2"
+	"os"
 
 	"github.com/ivanlemeshev/gameoflife/internal/app"
 )
 
 func main() {
-	application := app.New()
+	cfg := app.DefaultConfig()
+
+	flag.IntVar(&cfg.Width, "width", cfg.Width, "number of columns in the grid (the grid fits the terminal if neither width nor height is set)")
+	flag.IntVar(&cfg.Height, "height", cfg.Height, "number of rows in the grid (the grid fits the terminal if neither width nor height is set)")
+	flag.DurationVar(&cfg.TickInterval, "interval", cfg.TickInterval, "time between the generations")
+	flag.StringVar(&cfg.Rule, "rule", cfg.Rule, "rule in the B/S notation, e.g. B36/S23 (default: the rule of the pattern or B3/S23)")
+	flag.StringVar(&cfg.Topology, "topology", cfg.Topology, "topology of the grid: plane, torus, klein or cross (default: plane)")
+	flag.StringVar(&cfg.Engine, "engine", cfg.Engine, "engine of the cells: grid, bitgrid for the faster bit-packed grid on the plane, or sparse for the unbounded universe (default: grid)")
+	flag.IntVar(&cfg.Workers, "workers", cfg.Workers, "number of goroutines that calculate the next generation of the grid")
+	flag.StringVar(&cfg.PatternPath, "pattern", cfg.PatternPath, "pattern file loaded at startup (.rle, .cells, .lif or .mc)")
+	flag.StringVar(&cfg.SavePath, "save", cfg.SavePath, "pattern file the cells are saved to")
+	flag.Uint64Var(&cfg.Seed, "seed", cfg.Seed, "seed of the random cells (default: a new random seed)")
+	flag.Float64Var(&cfg.Density, "density", cfg.Density, "probability of a cell to be alive at startup and in the soups, from 0 to 1")
+	flag.IntVar(&cfg.SoupSize, "soup-size", cfg.SoupSize, "size of the centered square the soups are filled into (default: the whole visible area)")
+	flag.StringVar(&cfg.SoupRegion, "soup-region", cfg.SoupRegion, "rectangle the soups are filled into as WIDTHxHEIGHT+X+Y, e.g. 20x8+10+5 (default: the soup size)")
+	flag.StringVar(&cfg.Symmetry, "symmetry", cfg.Symmetry, "symmetry of the soups: C1, C2, C4, D4 or D8 (default: C1)")
+	flag.IntVar(&cfg.CensusDistance, "census-distance", cfg.CensusDistance, "largest distance between two cells of the same object in the census")
+	flag.StringVar(&cfg.CensusPath, "census", cfg.CensusPath, "CSV file the census of the objects is saved to")
+	flag.BoolVar(&cfg.AutoPause, "auto-pause", cfg.AutoPause, "pause the game when the cells become static or periodic")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [pattern file]\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	// The pattern file can also be passed as the only argument.
+	switch {
+	case flag.NArg() > 1:
+		log.Fatalf("Too many arguments: %v", flag.Args())
+	case flag.NArg() == 1 && cfg.PatternPath != "":
+		log.Fatalf("The pattern file is set both by the flag and the argument")
+	case flag.NArg() == 1:
+		cfg.PatternPath = flag.Arg(0)
+	}
+
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	// The grid fits the terminal unless its size is set.
+	cfg.FitTerminal = !setFlags["width"] && !setFlags["height"]
+
+	// A new seed is chosen every time unless it is set.
+	if !setFlags["seed"] {
+		cfg.Seed = rand.Uint64()
+	}
+
+	application, err := app.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create application: %v", err)
+	}
+
 	if err := application.Run(); err != nil {
 		log.Fatalf("Failed to run application: %v", err)
 	}
@@ -1,14 +1,19 @@
 package app
 
 import (
+	"fmt"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/ivanlemeshev/gameoflife/internal/game"
-)
-
-const (
-	gameGridWidth  = 40
-	gameGridHeight = 18
+	"github.com/ivanlemeshev/gameoflife/internal/game/bitgrid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/hashlife"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
 )
 
 // App is the main application structure.
@@ -17,13 +22,18 @@ type App struct {
 }
 
 // New creates a new application and initializes it.
-func New() *App {
+func New(cfg Config) (*App, error) {
+	opts, err := gameOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &App{
 		program: tea.NewProgram(
-			game.New(gameGridWidth, gameGridHeight),
+			game.New(cfg.Width, cfg.Height, opts...),
 			tea.WithAltScreen(),
 			tea.WithMouseAllMotion()),
-	}
+	}, nil
 }
 
 // Run starts the application.
@@ -31,3 +41,159 @@ func (a *App) Run() error {
 	_, err := a.program.Run()
 	return err
 }
+
+// gameOptions validates the settings and converts them into the options of
+// the game.
+func gameOptions(cfg Config) ([]game.Option, error) {
+	r, topology, symmetry, err := cfg.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	savePath := cfg.SavePath
+	if savePath == "" {
+		savePath = defaultSavePath
+	}
+
+	censusPath := cfg.CensusPath
+	if censusPath == "" {
+		censusPath = defaultCensusPath
+	}
+
+	// The seed is set even without the random cells at startup, so the soups
+	// filled later are different every time.
+	opts := []game.Option{
+		game.WithSavePath(savePath),
+		game.WithTickInterval(cfg.TickInterval),
+		game.WithRandomCells(cfg.Seed, cfg.Density),
+		game.WithSoup(cfg.SoupSize, symmetry),
+		game.WithCensus(cfg.CensusDistance, censusPath),
+	}
+
+	if cfg.SoupRegion != "" {
+		region, err := cfg.soupRegion()
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+
+		opts = append(opts, game.WithSoupRegion(region))
+	}
+
+	if cfg.FitTerminal {
+		opts = append(opts, game.WithFitTerminal())
+	}
+
+	if cfg.AutoPause {
+		opts = append(opts, game.WithAutoPause())
+	}
+
+	if cfg.PatternPath == "" {
+		engineOpt, err := cellEngine(cfg, r, rule.Conway, topology)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+
+		return append(opts, engineOpt), nil
+	}
+
+	patternOpts, err := loadPattern(cfg, r, topology)
+	if err != nil {
+		return nil, fmt.Errorf("load pattern: %w", err)
+	}
+
+	return append(opts, patternOpts...), nil
+}
+
+// loadPattern loads the pattern file and returns the options of the game to
+// place it. The Macrocell patterns that do not fit into the grid are loaded
+// into the HashLife universe as they are. The game decides it once the size
+// of the grid is known, which is after the first resize if the grid fits the
+// terminal.
+func loadPattern(cfg Config, r *rule.Rule, topology grid.Topology) ([]game.Option, error) {
+	if format, _ := pattern.FormatFromExtension(cfg.PatternPath); format != pattern.MC {
+		p, err := pattern.Load(cfg.PatternPath)
+		if err != nil {
+			return nil, err
+		}
+
+		engineOpt, err := cellEngine(cfg, r, p.Rule, topology)
+		if err != nil {
+			return nil, err
+		}
+
+		return []game.Option{engineOpt, game.WithPattern(p)}, nil
+	}
+
+	m, err := pattern.LoadMacrocell(cfg.PatternPath)
+	if err != nil {
+		return nil, err
+	}
+
+	engineOpt, err := cellEngine(cfg, r, m.Rule, topology)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []game.Option{engineOpt, game.WithMacrocell(m)}
+
+	universeRule := m.Rule
+	if r != nil {
+		universeRule = *r
+	}
+
+	var unsupported string
+
+	switch {
+	case topology != grid.Plane:
+		unsupported = fmt.Sprintf("topology %s", topology)
+	case universeRule.Born(0):
+		unsupported = fmt.Sprintf("rule %s", universeRule)
+	default:
+		return append(opts, game.WithQuadtreeEngine(func() engine.Engine {
+			return hashlife.New(hashlife.WithRule(universeRule))
+		})), nil
+	}
+
+	// Without the unbounded universe, the grid grows to fit the pattern, but
+	// not beyond its largest size, or beyond the set size.
+	width, height := cfg.Width, cfg.Height
+	if cfg.FitTerminal {
+		width, height = maxSize, maxSize
+	}
+
+	if bounds := m.BoundingBox(); bounds.Width() > width || bounds.Height() > height {
+		return nil, fmt.Errorf("pattern does not fit into the grid, and %s is not supported by the unbounded universe", unsupported)
+	}
+
+	return opts, nil
+}
+
+// cellEngine returns the option of the game to store the cells in the engine
+// chosen by the settings. The rule from the settings takes precedence over the
+// rule of the pattern. The rules with B0 of the patterns are checked here,
+// because the unbounded universe does not support them.
+func cellEngine(cfg Config, r *rule.Rule, patternRule rule.Rule, topology grid.Topology) (game.Option, error) {
+	engineRule := patternRule
+	if r != nil {
+		engineRule = *r
+	}
+
+	switch strings.ToLower(cfg.Engine) {
+	case engineBitGrid:
+		return game.WithEngine(func(width, height int) engine.Engine {
+			return bitgrid.New(width, height, bitgrid.WithRule(engineRule))
+		}), nil
+	case engineSparse:
+		if engineRule.Born(0) {
+			return nil, fmt.Errorf("engine %s does not support rule %s", engineSparse, engineRule)
+		}
+
+		return game.WithEngine(func(int, int) engine.Engine {
+			return sparse.New(sparse.WithRule(engineRule))
+		}), nil
+	default:
+		return game.WithEngine(func(width, height int) engine.Engine {
+			return grid.New(width, height, grid.WithRule(engineRule), grid.WithTopology(topology), grid.WithWorkers(cfg.Workers))
+		}), nil
+	}
+}
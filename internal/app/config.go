@@ -0,0 +1,230 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/census"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
+)
+
+const (
+	defaultWidth        = 40
+	defaultHeight       = 18
+	defaultTickInterval = 500 * time.Millisecond
+	defaultSavePath     = "gameoflife.rle"
+	defaultCensusPath   = "census.csv"
+	// engineGrid and engineBitGrid are the names of the engines that store
+	// the cells of the bounded grid, and engineSparse is the name of the
+	// unbounded universe that stores only the alive cells.
+	engineGrid    = "grid"
+	engineBitGrid = "bitgrid"
+	engineSparse  = "sparse"
+	// maxSize is the maximum width and height of the grid.
+	maxSize = 10000
+	// maxBitGridSize is the maximum width and height of the bit-packed grid.
+	// It keeps a bit per cell in two buffers, so the largest grid takes
+	// 1 GiB.
+	maxBitGridSize = pattern.MaxSize
+	// maxWorkers is the maximum number of goroutines that calculate the next
+	// generation of the grid.
+	maxWorkers = 256
+	// maxCensusDistance is the maximum distance between two cells of the
+	// same object in the census.
+	maxCensusDistance = 100
+)
+
+// Config contains the settings of the application.
+type Config struct {
+	// Width is the number of columns in the grid.
+	Width int
+	// Height is the number of rows in the grid.
+	Height int
+	// FitTerminal resizes the grid to fit the terminal, so the width and the
+	// height are only the size before the size of the terminal is known.
+	FitTerminal bool
+	// TickInterval is the time between the generations.
+	TickInterval time.Duration
+	// Rule is the rule in the B/S or S/B notation. If it is empty, the rule
+	// of the pattern or Conway's rule is used.
+	Rule string
+	// Topology is the name of the topology of the grid. If it is empty, the
+	// grid is a finite plane.
+	Topology string
+	// Engine is the name of the engine that stores the cells: grid, bitgrid
+	// or sparse. The bit-packed grid is faster, but it supports only the
+	// plane. The sparse universe is unbounded, so it supports neither the
+	// other topologies nor the rules with B0. If it is empty, the grid is
+	// used.
+	Engine string
+	// Workers is the number of goroutines that calculate the next generation
+	// of the grid in parallel. It is supported only by the grid engine.
+	Workers int
+	// PatternPath is the pattern file loaded at startup. It is optional.
+	PatternPath string
+	// SavePath is the pattern file the current state of the cells is saved to.
+	// The format is chosen by the extension.
+	SavePath string
+	// Seed is the seed of the random cells. The soups filled later use the
+	// following seeds.
+	Seed uint64
+	// Density is the probability of a cell to be alive at startup and in the
+	// soups. The grid is not filled with random cells at startup if it is
+	// zero.
+	Density float64
+	// SoupSize is the size of the centered square the soups are filled into.
+	// If it is zero, the soups fill the whole visible area.
+	SoupSize int
+	// SoupRegion is the rectangle the soups are filled into, like
+	// "20x8+10+5" for 20 columns and 8 rows from the cell (10, 5). It cannot
+	// be set together with the soup size.
+	SoupRegion string
+	// Symmetry is the name of the symmetry of the soups. If it is empty, the
+	// soups are not symmetric.
+	Symmetry string
+	// AutoPause pauses the game when the cells become static or periodic.
+	AutoPause bool
+	// CensusDistance is the largest distance between two cells of the same
+	// object in the census.
+	CensusDistance int
+	// CensusPath is the file the census of the objects is saved to.
+	CensusPath string
+}
+
+// DefaultConfig returns the default settings of the application.
+func DefaultConfig() Config {
+	return Config{
+		Width:          defaultWidth,
+		Height:         defaultHeight,
+		Workers:        1,
+		FitTerminal:    true,
+		TickInterval:   defaultTickInterval,
+		SavePath:       defaultSavePath,
+		CensusDistance: census.DefaultDistance,
+		CensusPath:     defaultCensusPath,
+	}
+}
+
+// validate checks the settings and parses the rule, the topology and the
+// symmetry of the soups.
+func (c Config) validate() (*rule.Rule, grid.Topology, soup.Symmetry, error) {
+	size := maxSize
+	if strings.EqualFold(c.Engine, engineBitGrid) {
+		size = maxBitGridSize
+	}
+
+	if c.Width < 1 || c.Width > size {
+		return nil, 0, 0, fmt.Errorf("invalid width %d: must be between 1 and %d", c.Width, size)
+	}
+
+	if c.Height < 1 || c.Height > size {
+		return nil, 0, 0, fmt.Errorf("invalid height %d: must be between 1 and %d", c.Height, size)
+	}
+
+	if c.TickInterval <= 0 {
+		return nil, 0, 0, fmt.Errorf("invalid tick interval %s: must be positive", c.TickInterval)
+	}
+
+	if c.Density < 0 || c.Density > 1 {
+		return nil, 0, 0, fmt.Errorf("invalid density %g: must be between 0 and 1", c.Density)
+	}
+
+	if c.SoupSize < 0 || c.SoupSize > maxSize {
+		return nil, 0, 0, fmt.Errorf("invalid soup size %d: must be between 0 and %d", c.SoupSize, maxSize)
+	}
+
+	if c.SoupRegion != "" {
+		if _, err := c.soupRegion(); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	if c.Workers < 1 || c.Workers > maxWorkers {
+		return nil, 0, 0, fmt.Errorf("invalid workers %d: must be between 1 and %d", c.Workers, maxWorkers)
+	}
+
+	if c.CensusDistance < 1 || c.CensusDistance > maxCensusDistance {
+		return nil, 0, 0, fmt.Errorf("invalid census distance %d: must be between 1 and %d", c.CensusDistance, maxCensusDistance)
+	}
+
+	var r *rule.Rule
+	if c.Rule != "" {
+		parsed, err := rule.Parse(c.Rule)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		r = &parsed
+	}
+
+	topology := grid.Plane
+	if c.Topology != "" {
+		parsed, err := grid.ParseTopology(c.Topology)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		topology = parsed
+	}
+
+	switch strings.ToLower(c.Engine) {
+	case "", engineGrid:
+	case engineBitGrid:
+		if topology != grid.Plane {
+			return nil, 0, 0, fmt.Errorf("engine %s does not support topology %s", engineBitGrid, topology)
+		}
+
+		if c.Workers > 1 {
+			return nil, 0, 0, fmt.Errorf("engine %s does not support workers", engineBitGrid)
+		}
+	case engineSparse:
+		if topology != grid.Plane {
+			return nil, 0, 0, fmt.Errorf("engine %s does not support topology %s", engineSparse, topology)
+		}
+
+		if c.Workers > 1 {
+			return nil, 0, 0, fmt.Errorf("engine %s does not support workers", engineSparse)
+		}
+
+		if r != nil && r.Born(0) {
+			return nil, 0, 0, fmt.Errorf("engine %s does not support rule %s", engineSparse, r)
+		}
+	default:
+		return nil, 0, 0, fmt.Errorf("invalid engine %q: expected %s, %s or %s", c.Engine, engineGrid, engineBitGrid, engineSparse)
+	}
+
+	symmetry := soup.C1
+	if c.Symmetry != "" {
+		parsed, err := soup.ParseSymmetry(c.Symmetry)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		symmetry = parsed
+	}
+
+	return r, topology, symmetry, nil
+}
+
+// soupRegion parses the region of the soups and checks its size.
+func (c Config) soupRegion() (engine.Rect, error) {
+	if c.SoupSize > 0 {
+		return engine.Rect{}, fmt.Errorf("soup region %s and soup size %d cannot be both set", c.SoupRegion, c.SoupSize)
+	}
+
+	r, err := engine.ParseRect(c.SoupRegion)
+	if err != nil {
+		return engine.Rect{}, err
+	}
+
+	if r.Width() > maxSize || r.Height() > maxSize {
+		return engine.Rect{}, fmt.Errorf("invalid soup region %s: the size must not exceed %d", c.SoupRegion, maxSize)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,315 @@
+package app_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/app"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+
+	rlePath := filepath.Join(dir, "glider.rle")
+	require.NoError(t, os.WriteFile(rlePath, []byte("x = 3, y = 3, rule = B36/S23\nbo$2bo$3o!\n"), 0o600))
+
+	// The blocks are too far from each other to fit into the grid.
+	mcPath := filepath.Join(dir, "blocks.mc")
+	require.NoError(t, os.WriteFile(mcPath, []byte("[M2]\n**$**$\n4 1 0 0 0\n5 2 0 0 2\n"), 0o600))
+
+	tt := []struct {
+		name   string
+		modify func(cfg *app.Config)
+	}{
+		{
+			name:   "default config",
+			modify: func(*app.Config) {},
+		},
+		{
+			name: "custom size, rule and topology",
+			modify: func(cfg *app.Config) {
+				cfg.Width = 100
+				cfg.Height = 50
+				cfg.TickInterval = 10 * time.Millisecond
+				cfg.Rule = "23/36"
+				cfg.Topology = "torus"
+			},
+		},
+		{
+			name: "bit-packed grid",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "bitgrid"
+				cfg.Rule = "B36/S23"
+			},
+		},
+		{
+			name: "large bit-packed grid",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "bitgrid"
+				cfg.Width = 12000
+				cfg.Height = 12000
+				cfg.FitTerminal = false
+			},
+		},
+		{
+			name: "sparse universe with pattern",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "sparse"
+				cfg.PatternPath = rlePath
+			},
+		},
+		{
+			name: "workers",
+			modify: func(cfg *app.Config) {
+				cfg.Workers = 4
+			},
+		},
+		{
+			name: "random cells",
+			modify: func(cfg *app.Config) {
+				cfg.Seed = 42
+				cfg.Density = 0.3
+			},
+		},
+		{
+			name: "symmetric soup",
+			modify: func(cfg *app.Config) {
+				cfg.Seed = 42
+				cfg.Density = 0.5
+				cfg.SoupSize = 16
+				cfg.Symmetry = "d8"
+			},
+		},
+		{
+			name: "soup region",
+			modify: func(cfg *app.Config) {
+				cfg.Density = 0.5
+				cfg.SoupRegion = "20x8-10+5"
+			},
+		},
+		{
+			name: "auto-pause",
+			modify: func(cfg *app.Config) {
+				cfg.AutoPause = true
+			},
+		},
+		{
+			name: "census",
+			modify: func(cfg *app.Config) {
+				cfg.CensusDistance = 1
+				cfg.CensusPath = filepath.Join(dir, "objects.csv")
+			},
+		},
+		{
+			name: "pattern",
+			modify: func(cfg *app.Config) {
+				cfg.PatternPath = rlePath
+			},
+		},
+		{
+			name: "Macrocell pattern on torus fitted to the terminal",
+			modify: func(cfg *app.Config) {
+				cfg.Width = 10
+				cfg.Height = 10
+				cfg.Topology = "torus"
+				cfg.PatternPath = mcPath
+			},
+		},
+		{
+			name: "huge Macrocell pattern",
+			modify: func(cfg *app.Config) {
+				cfg.Width = 10
+				cfg.Height = 10
+				cfg.PatternPath = mcPath
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := app.DefaultConfig()
+			tc.modify(&cfg)
+
+			_, err := app.New(cfg)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	mcPath := filepath.Join(t.TempDir(), "blocks.mc")
+	require.NoError(t, os.WriteFile(mcPath, []byte("[M2]\n**$**$\n4 1 0 0 0\n5 2 0 0 2\n"), 0o600))
+
+	b0Path := filepath.Join(t.TempDir(), "b0.rle")
+	require.NoError(t, os.WriteFile(b0Path, []byte("x = 1, y = 1, rule = B0/S8\no!\n"), 0o600))
+
+	tt := []struct {
+		name     string
+		modify   func(cfg *app.Config)
+		expected string
+	}{
+		{
+			name:     "zero width",
+			modify:   func(cfg *app.Config) { cfg.Width = 0 },
+			expected: "invalid config: invalid width 0: must be between 1 and 10000",
+		},
+		{
+			name:     "too large height",
+			modify:   func(cfg *app.Config) { cfg.Height = 10001 },
+			expected: "invalid config: invalid height 10001: must be between 1 and 10000",
+		},
+		{
+			name: "too large bit-packed grid",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "bitgrid"
+				cfg.Width = 65537
+			},
+			expected: "invalid config: invalid width 65537: must be between 1 and 65536",
+		},
+		{
+			name:     "negative tick interval",
+			modify:   func(cfg *app.Config) { cfg.TickInterval = -time.Second },
+			expected: "invalid config: invalid tick interval -1s: must be positive",
+		},
+		{
+			name:     "density above one",
+			modify:   func(cfg *app.Config) { cfg.Density = 1.5 },
+			expected: "invalid config: invalid density 1.5: must be between 0 and 1",
+		},
+		{
+			name:     "negative soup size",
+			modify:   func(cfg *app.Config) { cfg.SoupSize = -1 },
+			expected: "invalid config: invalid soup size -1: must be between 0 and 10000",
+		},
+		{
+			name:     "invalid soup region",
+			modify:   func(cfg *app.Config) { cfg.SoupRegion = "20x8" },
+			expected: `invalid config: invalid rectangle "20x8": expected WIDTHxHEIGHT+X+Y`,
+		},
+		{
+			name:     "too large soup region",
+			modify:   func(cfg *app.Config) { cfg.SoupRegion = "10001x8+0+0" },
+			expected: "invalid config: invalid soup region 10001x8+0+0: the size must not exceed 10000",
+		},
+		{
+			name: "soup region and size",
+			modify: func(cfg *app.Config) {
+				cfg.SoupRegion = "20x8+0+0"
+				cfg.SoupSize = 16
+			},
+			expected: "invalid config: soup region 20x8+0+0 and soup size 16 cannot be both set",
+		},
+		{
+			name:     "invalid symmetry",
+			modify:   func(cfg *app.Config) { cfg.Symmetry = "D2" },
+			expected: `invalid config: invalid symmetry "D2": expected C1, C2, C4, D4 or D8`,
+		},
+		{
+			name:     "zero census distance",
+			modify:   func(cfg *app.Config) { cfg.CensusDistance = 0 },
+			expected: "invalid config: invalid census distance 0: must be between 1 and 100",
+		},
+		{
+			name:     "invalid rule",
+			modify:   func(cfg *app.Config) { cfg.Rule = "B9/S23" },
+			expected: `invalid config: invalid rule "B9/S23": unexpected character '9'`,
+		},
+		{
+			name:     "invalid topology",
+			modify:   func(cfg *app.Config) { cfg.Topology = "sphere" },
+			expected: `invalid config: invalid topology "sphere": expected plane, torus, klein or cross`,
+		},
+		{
+			name:     "invalid engine",
+			modify:   func(cfg *app.Config) { cfg.Engine = "quadtree" },
+			expected: `invalid config: invalid engine "quadtree": expected grid, bitgrid or sparse`,
+		},
+		{
+			name: "bit-packed grid on torus",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "bitgrid"
+				cfg.Topology = "torus"
+			},
+			expected: "invalid config: engine bitgrid does not support topology torus",
+		},
+		{
+			name:     "zero workers",
+			modify:   func(cfg *app.Config) { cfg.Workers = 0 },
+			expected: "invalid config: invalid workers 0: must be between 1 and 256",
+		},
+		{
+			name: "bit-packed grid with workers",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "bitgrid"
+				cfg.Workers = 2
+			},
+			expected: "invalid config: engine bitgrid does not support workers",
+		},
+		{
+			name: "sparse universe on torus",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "sparse"
+				cfg.Topology = "torus"
+			},
+			expected: "invalid config: engine sparse does not support topology torus",
+		},
+		{
+			name: "sparse universe with workers",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "sparse"
+				cfg.Workers = 2
+			},
+			expected: "invalid config: engine sparse does not support workers",
+		},
+		{
+			name: "sparse universe with B0",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "sparse"
+				cfg.Rule = "B0/S8"
+			},
+			expected: "invalid config: engine sparse does not support rule B0/S8",
+		},
+		{
+			name: "sparse universe with B0 pattern",
+			modify: func(cfg *app.Config) {
+				cfg.Engine = "sparse"
+				cfg.PatternPath = b0Path
+			},
+			expected: "load pattern: engine sparse does not support rule B0/S8",
+		},
+		{
+			name:   "missing pattern file",
+			modify: func(cfg *app.Config) { cfg.PatternPath = filepath.Join(t.TempDir(), "missing.rle") },
+		},
+		{
+			name: "huge Macrocell pattern on torus",
+			modify: func(cfg *app.Config) {
+				cfg.Width = 10
+				cfg.Height = 10
+				cfg.FitTerminal = false
+				cfg.Topology = "torus"
+				cfg.PatternPath = mcPath
+			},
+			expected: "load pattern: pattern does not fit into the grid, and topology torus is not supported by the unbounded universe",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := app.DefaultConfig()
+			tc.modify(&cfg)
+
+			_, err := app.New(cfg)
+			require.Error(t, err)
+
+			if tc.expected != "" {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
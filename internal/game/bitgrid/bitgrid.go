@@ -0,0 +1,385 @@
+package bitgrid
+
+import (
+	"math/bits"
+	"slices"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+// wordSize is the number of cells stored in one word.
+const wordSize = 64
+
+var (
+	_ engine.Resizable  = (*Grid)(nil)
+	_ engine.Rewindable = (*Grid)(nil)
+	_ engine.Counter    = (*Grid)(nil)
+)
+
+// Grid represents a bounded grid of cells packed into bits. Each row is
+// stored as a slice of words with 64 cells per word, and the next generation
+// is calculated for all cells of a word at once with bitwise operations. The
+// cells outside the grid are considered to be dead.
+type Grid struct {
+	generation   int
+	width        int
+	height       int
+	wordsPerRow  int
+	lastWordMask uint64
+	rule         rule.Rule
+	cells        []uint64
+	next         []uint64
+	// population, births, deaths and bounds are counted while the next
+	// generation is calculated, and the population and the bounds are
+	// updated by the edits. The bounds are found again only when an edit
+	// kills a cell on their border.
+	population  int
+	births      int
+	deaths      int
+	edited      bool
+	bounds      engine.Rect
+	boundsKnown bool
+}
+
+// Option configures the grid.
+type Option func(*Grid)
+
+// WithRule sets the rule used to calculate the next generation of the cells.
+// By default, Conway's rule (B3/S23) is used.
+func WithRule(r rule.Rule) Option {
+	return func(g *Grid) {
+		g.rule = r
+	}
+}
+
+// New creates a new grid with the given width and height. All cells are dead in the beginning.
+func New(width, height int, opts ...Option) *Grid {
+	wordsPerRow := (width + wordSize - 1) / wordSize
+
+	g := &Grid{
+		width:        width,
+		height:       height,
+		wordsPerRow:  wordsPerRow,
+		lastWordMask: ^uint64(0) >> (wordsPerRow*wordSize - width),
+		rule:         rule.Conway,
+		cells:        make([]uint64, wordsPerRow*height),
+		next:         make([]uint64, wordsPerRow*height),
+		boundsKnown:  true,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// Width returns the number of columns in the grid.
+func (g *Grid) Width() int {
+	return g.width
+}
+
+// Height returns the number of rows in the grid.
+func (g *Grid) Height() int {
+	return g.height
+}
+
+// Resize changes the number of columns and rows of the grid keeping the
+// generation and the cells in the same columns and rows. The cells outside
+// the new size are removed.
+func (g *Grid) Resize(width, height int) {
+	resized := New(width, height, WithRule(g.rule))
+	resized.generation = g.generation
+
+	for y := range min(height, g.height) {
+		row := g.row(g.cells, y)
+		copy(resized.row(resized.cells, y), row)
+
+		// The cells of the last word that are outside the new width are
+		// removed.
+		if width < g.width && resized.wordsPerRow > 0 {
+			resizedRow := resized.row(resized.cells, y)
+			resizedRow[len(resizedRow)-1] &= resized.lastWordMask
+		}
+	}
+
+	// The removed cells are not known, so the remaining ones are counted.
+	for _, word := range resized.cells {
+		resized.population += bits.OnesCount64(word)
+	}
+
+	resized.edited = true
+	resized.boundsKnown = false
+	*g = *resized
+}
+
+// Rule returns the rule used to calculate the next generation of the cells.
+func (g *Grid) Rule() rule.Rule {
+	return g.rule
+}
+
+// Generation returns the current generation of the grid.
+func (g *Grid) Generation() int {
+	return g.generation
+}
+
+// SetGeneration sets the current generation of the grid without changing
+// the cells.
+func (g *Grid) SetGeneration(generation int) {
+	g.edited = g.edited || generation != g.generation
+	g.generation = generation
+}
+
+// Population returns the number of alive cells.
+func (g *Grid) Population() int {
+	return g.population
+}
+
+// Cell returns the cell in the x-th column and y-th row.
+// The cells outside the grid are dead.
+func (g *Grid) Cell(x, y int) *cell.Cell {
+	if !g.contains(x, y) {
+		return cell.Dead
+	}
+
+	if g.cells[g.index(x, y)]&(1<<(x%wordSize)) == 0 {
+		return cell.Dead
+	}
+
+	return cell.Alive
+}
+
+// SetCell sets the cell in the x-th column and y-th row.
+// The cells outside the grid are ignored.
+func (g *Grid) SetCell(x, y int, c *cell.Cell) {
+	if !g.contains(x, y) {
+		return
+	}
+
+	i := g.index(x, y)
+	word := g.cells[i]
+
+	if c == cell.Alive {
+		g.cells[i] |= 1 << (x % wordSize)
+	} else {
+		g.cells[i] &^= 1 << (x % wordSize)
+	}
+
+	switch {
+	case g.cells[i] == word:
+		return
+	case c == cell.Alive:
+		g.population++
+		g.bounds = g.bounds.Union(engine.Rect{MinX: x, MinY: y, MaxX: x + 1, MaxY: y + 1})
+	default:
+		g.population--
+		g.boundsKnown = g.boundsKnown && !g.bounds.Border(x, y)
+	}
+
+	g.edited = true
+}
+
+// BoundingBox returns the smallest rectangle that contains all alive cells.
+// The cells are scanned only if an edit made the last bounds unknown.
+func (g *Grid) BoundingBox() engine.Rect {
+	if g.boundsKnown {
+		return g.bounds
+	}
+
+	g.bounds = engine.Rect{}
+	for y := range g.height {
+		g.bounds = g.bounds.Union(rowBounds(g.row(g.cells, y), y))
+	}
+
+	g.boundsKnown = true
+
+	return g.bounds
+}
+
+// Changes returns the number of cells born and the number of cells died in
+// the last generation. It returns false if the grid is edited since.
+func (g *Grid) Changes() (births, deaths int, ok bool) {
+	return g.births, g.deaths, !g.edited
+}
+
+// NextGeneration moves the grid to the next generation.
+func (g *Grid) NextGeneration() {
+	g.generation++
+
+	if g.wordsPerRow == 0 {
+		return
+	}
+
+	birth, survival := g.conditions()
+
+	g.births, g.deaths, g.bounds = 0, 0, engine.Rect{}
+
+	for y := range g.height {
+		above := g.row(g.cells, y-1)
+		current := g.row(g.cells, y)
+		below := g.row(g.cells, y+1)
+		next := g.row(g.next, y)
+
+		for w := range next {
+			next[w] = nextWord(above, current, below, w, birth, survival)
+		}
+
+		next[len(next)-1] &= g.lastWordMask
+
+		// The born cells are alive only in the next word, and the died ones
+		// only in the current word.
+		for w := range next {
+			g.births += bits.OnesCount64(next[w] &^ current[w])
+			g.deaths += bits.OnesCount64(current[w] &^ next[w])
+		}
+
+		g.bounds = g.bounds.Union(rowBounds(next, y))
+	}
+
+	g.population += g.births - g.deaths
+	g.cells, g.next = g.next, g.cells
+	g.edited = false
+	g.boundsKnown = true
+}
+
+// rowBounds returns the bounds of the alive cells of the y-th row, from the
+// first bit of its first non-zero word to the last bit of its last one.
+func rowBounds(row []uint64, y int) engine.Rect {
+	first := slices.IndexFunc(row, func(word uint64) bool { return word != 0 })
+	if first < 0 {
+		return engine.Rect{}
+	}
+
+	last := len(row) - 1
+	for row[last] == 0 {
+		last--
+	}
+
+	return engine.Rect{
+		MinX: first*wordSize + bits.TrailingZeros64(row[first]),
+		MinY: y,
+		MaxX: last*wordSize + wordSize - bits.LeadingZeros64(row[last]),
+		MaxY: y + 1,
+	}
+}
+
+// conditions returns the numbers of alive neighbors for which a dead cell is
+// born and an alive cell survives.
+func (g *Grid) conditions() ([]int, []int) {
+	var birth, survival []int
+
+	for n := 0; n <= 8; n++ {
+		if g.rule.Born(n) {
+			birth = append(birth, n)
+		}
+
+		if g.rule.Survives(n) {
+			survival = append(survival, n)
+		}
+	}
+
+	return birth, survival
+}
+
+// row returns the words of the y-th row, or nil if the row is outside the grid.
+func (g *Grid) row(cells []uint64, y int) []uint64 {
+	if y < 0 || y >= g.height {
+		return nil
+	}
+
+	return cells[y*g.wordsPerRow : (y+1)*g.wordsPerRow]
+}
+
+// index returns the index of the word with the cell in the x-th column and y-th row.
+func (g *Grid) index(x, y int) int {
+	return y*g.wordsPerRow + x/wordSize
+}
+
+// contains returns true if the cell in the x-th column and y-th row is inside the grid.
+func (g *Grid) contains(x, y int) bool {
+	return x >= 0 && x < g.width && y >= 0 && y < g.height
+}
+
+// nextWord calculates the next generation of the w-th word of the current row.
+func nextWord(above, current, below []uint64, w int, birth, survival []int) uint64 {
+	// The eight neighbors of every cell in the word are represented by
+	// eight words, where the bit of each cell holds the state of one of its
+	// neighbors.
+	aboveWest, aboveCenter, aboveEast := neighbors(above, w)
+	west, center, east := neighbors(current, w)
+	belowWest, belowCenter, belowEast := neighbors(below, w)
+
+	// The neighbors are summed up with the adder logic, so each cell gets the
+	// number of its alive neighbors in four bits (ones, twos, fours, eights).
+	sum1, carry1 := fullAdder(aboveWest, aboveCenter, aboveEast)
+	sum2, carry2 := fullAdder(west, east, belowWest)
+	sum3, carry3 := halfAdder(belowCenter, belowEast)
+	ones, carry4 := fullAdder(sum1, sum2, sum3)
+	sum5, carry5 := fullAdder(carry1, carry2, carry3)
+	twos, carry6 := halfAdder(sum5, carry4)
+	fours, eights := halfAdder(carry5, carry6)
+
+	count := [4]uint64{ones, twos, fours, eights}
+
+	var born, survived uint64
+	for _, n := range birth {
+		born |= equals(count, n)
+	}
+
+	for _, n := range survival {
+		survived |= equals(count, n)
+	}
+
+	return (^center & born) | (center & survived)
+}
+
+// neighbors returns the w-th word of the row and the words where each bit
+// holds the state of the west and east neighbors of the cell.
+func neighbors(row []uint64, w int) (uint64, uint64, uint64) {
+	if row == nil {
+		return 0, 0, 0
+	}
+
+	center := row[w]
+	west := center << 1
+	east := center >> 1
+
+	if w > 0 {
+		west |= row[w-1] >> (wordSize - 1)
+	}
+
+	if w < len(row)-1 {
+		east |= row[w+1] << (wordSize - 1)
+	}
+
+	return west, center, east
+}
+
+// equals returns the word where the bits are set for the cells that have
+// exactly n alive neighbors.
+func equals(count [4]uint64, n int) uint64 {
+	result := ^uint64(0)
+	for i, bit := range count {
+		if n&(1<<i) != 0 {
+			result &= bit
+		} else {
+			result &^= bit
+		}
+	}
+
+	return result
+}
+
+// halfAdder adds two bits for every cell.
+func halfAdder(a, b uint64) (uint64, uint64) {
+	return a ^ b, a & b
+}
+
+// fullAdder adds three bits for every cell.
+func fullAdder(a, b, c uint64) (uint64, uint64) {
+	sum := a ^ b
+
+	return sum ^ c, (a & b) | (sum & c)
+}
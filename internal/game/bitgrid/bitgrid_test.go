@@ -0,0 +1,193 @@
+package bitgrid_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/bitgrid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
+)
+
+func TestGrid_SetCell(t *testing.T) {
+	bg := bitgrid.New(130, 2)
+
+	for _, x := range []int{0, 63, 64, 127, 128, 129} {
+		bg.SetCell(x, 1, cell.Alive)
+		assert.Equal(t, cell.Alive, bg.Cell(x, 1))
+	}
+
+	// Setting an alive cell again does not count it twice.
+	bg.SetCell(63, 1, cell.Alive)
+	assert.Equal(t, 6, bg.Population())
+	assert.Equal(t, engine.Rect{MinX: 0, MinY: 1, MaxX: 130, MaxY: 2}, bg.BoundingBox())
+
+	// The bounds shrink when a cell on their border dies.
+	bg.SetCell(129, 1, cell.Dead)
+	bg.SetCell(128, 1, cell.Dead)
+	assert.Equal(t, engine.Rect{MinX: 0, MinY: 1, MaxX: 128, MaxY: 2}, bg.BoundingBox())
+	bg.SetCell(128, 1, cell.Alive)
+	bg.SetCell(129, 1, cell.Alive)
+
+	// The cells outside the grid are ignored and always dead.
+	bg.SetCell(130, 1, cell.Alive)
+	bg.SetCell(0, -1, cell.Alive)
+	assert.Equal(t, cell.Dead, bg.Cell(130, 1))
+	assert.Equal(t, cell.Dead, bg.Cell(0, -1))
+
+	bg.SetCell(64, 1, cell.Dead)
+	assert.Equal(t, cell.Dead, bg.Cell(64, 1))
+	assert.Equal(t, 5, bg.Population())
+}
+
+func TestGrid_Resize(t *testing.T) {
+	bg := bitgrid.New(130, 4)
+	bg.SetCell(1, 1, cell.Alive)
+	bg.SetCell(70, 2, cell.Alive)
+	bg.SetCell(129, 3, cell.Alive)
+	bg.SetGeneration(5)
+
+	bg.Resize(200, 5)
+	assert.Equal(t, 200, bg.Width())
+	assert.Equal(t, 5, bg.Height())
+	assert.Equal(t, 5, bg.Generation())
+	assert.Equal(t, []engine.Point{{X: 1, Y: 1}, {X: 70, Y: 2}, {X: 129, Y: 3}}, engine.AliveCells(bg))
+
+	bg.SetCell(199, 4, cell.Alive)
+	assert.Equal(t, cell.Alive, bg.Cell(199, 4))
+
+	// The cells outside the new size are removed, also in the same word.
+	bg.Resize(70, 3)
+	assert.Equal(t, []engine.Point{{X: 1, Y: 1}}, engine.AliveCells(bg))
+	assert.Equal(t, 1, bg.Population())
+
+	bg.Resize(130, 4)
+	assert.Equal(t, cell.Dead, bg.Cell(70, 2))
+}
+
+func TestGrid_NextGeneration(t *testing.T) {
+	tt := []struct {
+		name   string
+		width  int
+		height int
+		rule   rule.Rule
+	}{
+		{
+			name:   "single word per row",
+			width:  64,
+			height: 64,
+			rule:   rule.Conway,
+		},
+		{
+			name:   "partial last word",
+			width:  100,
+			height: 70,
+			rule:   rule.Conway,
+		},
+		{
+			name:   "several words per row",
+			width:  200,
+			height: 3,
+			rule:   rule.Conway,
+		},
+		{
+			name:   "single column",
+			width:  1,
+			height: 10,
+			rule:   rule.Conway,
+		},
+		{
+			name:   "HighLife",
+			width:  90,
+			height: 90,
+			rule:   rule.HighLife,
+		},
+		{
+			name:   "Day & Night",
+			width:  90,
+			height: 90,
+			rule:   rule.DayAndNight,
+		},
+		{
+			name:   "rule with B0",
+			width:  70,
+			height: 20,
+			rule:   rule.MustParse("B0123/S8"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			bg := bitgrid.New(tc.width, tc.height, bitgrid.WithRule(tc.rule))
+			sg := grid.New(tc.width, tc.height, grid.WithRule(tc.rule))
+			r := engine.Rect{MaxX: tc.width, MaxY: tc.height}
+			soup.Fill(bg, r, 1, 0.5, soup.C1)
+			soup.Fill(sg, r, 1, 0.5, soup.C1)
+
+			for range 50 {
+				bg.NextGeneration()
+				sg.NextGeneration()
+
+				assert.Equal(t, engine.AliveCells(sg), engine.AliveCells(bg))
+
+				// The cells counted by the grid while stepping are the same.
+				births, deaths, ok := bg.Changes()
+				expectedBirths, expectedDeaths, _ := sg.Changes()
+				assert.True(t, ok)
+				assert.Equal(t, expectedBirths, births)
+				assert.Equal(t, expectedDeaths, deaths)
+				assert.Equal(t, sg.BoundingBox(), bg.BoundingBox())
+				assert.Equal(t, len(engine.AliveCells(sg)), sg.Population())
+				assert.Equal(t, sg.Population(), bg.Population())
+			}
+
+			assert.Equal(t, 50, bg.Generation())
+		})
+	}
+}
+
+func BenchmarkNextGeneration(b *testing.B) {
+	newEngines := []struct {
+		name      string
+		newEngine func(size int) engine.Engine
+		maxSize   int
+	}{
+		{
+			name: "grid",
+			newEngine: func(size int) engine.Engine {
+				return grid.New(size, size)
+			},
+			maxSize: 2048,
+		},
+		{
+			name: "bitgrid",
+			newEngine: func(size int) engine.Engine {
+				return bitgrid.New(size, size)
+			},
+			maxSize: 16384,
+		},
+	}
+
+	for _, ne := range newEngines {
+		for _, size := range []int{128, 512, 2048, 10000, 16384} {
+			if size > ne.maxSize {
+				continue
+			}
+
+			b.Run(fmt.Sprintf("%s/%dx%d", ne.name, size, size), func(b *testing.B) {
+				e := ne.newEngine(size)
+				soup.Fill(e, engine.Rect{MaxX: size, MaxY: size}, 1, 0.5, soup.C1)
+
+				b.ResetTimer()
+				for range b.N {
+					e.NextGeneration()
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ivanlemeshev/gameoflife/internal/game/library"
+	"github.com/ivanlemeshev/gameoflife/internal/game/render"
+)
+
+const (
+	// maxBrowserListWidth is the largest number of columns taken by the list
+	// of the patterns. The rest is taken by the preview.
+	maxBrowserListWidth = 40
+	// defaultBrowserWidth and defaultBrowserHeight are the size of the
+	// browser before the size of the terminal is known.
+	defaultBrowserWidth  = 80
+	defaultBrowserHeight = 20
+)
+
+// libraryItem is a pattern of the library in the list of the browser.
+type libraryItem struct {
+	entry library.Entry
+}
+
+// Title returns the name of the pattern.
+func (i libraryItem) Title() string {
+	return i.entry.Pattern.Name
+}
+
+// Description returns the category and the period of the pattern.
+func (i libraryItem) Description() string {
+	if i.entry.Period == 0 {
+		return i.entry.Category.String()
+	}
+
+	return fmt.Sprintf("%s, period %d", i.entry.Category, i.entry.Period)
+}
+
+// FilterValue returns the text the search matches against, so the patterns
+// can be found by the category as well.
+func (i libraryItem) FilterValue() string {
+	return i.entry.Pattern.Name + " " + i.entry.Category.String()
+}
+
+// openLibrary shows the browser of the patterns of the library instead of the
+// cells.
+func (g *Game) openLibrary() {
+	entries, err := library.Entries()
+	if err != nil {
+		g.status = fmt.Sprintf("Failed to open the library: %v", err)
+		return
+	}
+
+	items := make([]list.Item, len(entries))
+	for i, e := range entries {
+		items[i] = libraryItem{entry: e}
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Pattern library"
+	l.SetStatusBarItemName("pattern", "patterns")
+
+	// The browser is closed instead, but the game can still be quit.
+	l.DisableQuitKeybindings()
+	l.KeyMap.ForceQuit.SetEnabled(true)
+
+	g.browser = &l
+	g.resizeBrowser()
+}
+
+// handleBrowserKey selects a pattern in the browser. The search is handled by
+// the list, so the keys of the game work only when the search is not typed.
+func (g *Game) handleBrowserKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if g.browser.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, g.keys.Library),
+			g.browser.FilterState() == list.Unfiltered && msg.Type == tea.KeyEsc:
+			g.browser = nil
+			return g, nil
+		case msg.Type == tea.KeyEnter:
+			if item, ok := g.browser.SelectedItem().(libraryItem); ok {
+				g.placeLibraryEntry(item.entry)
+			}
+
+			g.browser = nil
+
+			return g, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	*g.browser, cmd = g.browser.Update(msg)
+
+	return g, cmd
+}
+
+// placeLibraryEntry stamps the pattern under the cursor, or in the center of
+// the viewport if the cursor is hidden. The pattern is put into the
+// clipboard, so more copies can be pasted.
+func (g *Game) placeLibraryEntry(e library.Entry) {
+	g.clipboard = e.Pattern
+	g.startPasting()
+	g.stamp()
+	g.pasting = false
+	g.status = fmt.Sprintf("Placed %s.", e.Pattern.Name)
+}
+
+// resizeBrowser fits the list of the browser into the area of the cells and
+// the timeline.
+func (g *Game) resizeBrowser() {
+	if g.browser == nil {
+		return
+	}
+
+	width, height := defaultBrowserWidth, defaultBrowserHeight
+	if g.terminalWidth > 0 && g.terminalHeight > 0 {
+		width = g.terminalWidth
+		height = max(g.terminalHeight-len(header)-footerHeight+1, 1)
+	}
+
+	g.browser.SetSize(min(width/2, maxBrowserListWidth), height)
+}
+
+// renderBrowser renders the list of the patterns with the details and the
+// preview of the selected one next to it.
+func (g *Game) renderBrowser(sb *strings.Builder) {
+	details := ""
+	if item, ok := g.browser.SelectedItem().(libraryItem); ok {
+		details = g.libraryEntryDetails(item.entry)
+	}
+
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, g.browser.View(), details))
+	sb.WriteString("\n")
+}
+
+// libraryEntryDetails returns what is known about the pattern and its
+// preview drawn with the current renderer. The preview is cropped to the
+// terminal.
+func (g *Game) libraryEntryDetails(e library.Entry) string {
+	lines := []string{
+		e.Pattern.Name,
+		"Category: " + e.Category.String(),
+		fmt.Sprintf("Size: %dx%d", e.Pattern.Width, e.Pattern.Height),
+	}
+
+	if e.Period > 0 {
+		lines = append(lines, fmt.Sprintf("Period: %d", e.Period))
+	}
+
+	if e.Speed != "" {
+		lines = append(lines, "Speed: "+e.Speed)
+	}
+
+	lines = append(lines, "Discovered by: "+e.Pattern.Author, "")
+
+	width := defaultBrowserWidth
+	if g.terminalWidth > 0 {
+		width = g.terminalWidth
+	}
+
+	// The preview is placed after a margin of two columns.
+	scaleX, scaleY := g.renderer.Scale()
+	columns := max(width-g.browser.Width()-2, 0) / g.renderer.CharWidth() * scaleX
+	rows := max(g.browser.Height()-len(lines), 0) * scaleY
+
+	pixels := make([][]render.Pixel, min(e.Pattern.Height, rows))
+	for y := range pixels {
+		pixels[y] = make([]render.Pixel, min(e.Pattern.Width, columns))
+		for x := range pixels[y] {
+			pixels[y][x] = render.Dead
+		}
+	}
+
+	for _, p := range e.Pattern.Cells {
+		if p.Y < len(pixels) && p.X < len(pixels[p.Y]) {
+			pixels[p.Y][p.X] = render.Alive
+		}
+	}
+
+	if len(pixels) > 0 && len(pixels[0]) > 0 {
+		lines = append(lines, g.renderer.Render(pixels)...)
+	}
+
+	return lipgloss.NewStyle().MarginLeft(2).Render(strings.Join(lines, "\n"))
+}
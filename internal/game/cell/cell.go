@@ -1,5 +1,7 @@
 package cell
 
+import "github.com/ivanlemeshev/gameoflife/internal/game/rule"
+
 var (
 	Alive = &Cell{}
 	Dead  *Cell
@@ -8,34 +10,18 @@ var (
 // Cell represents a cell in the Conway's Game of Life.
 type Cell struct{}
 
-// NextGeneration calculates the next generation of the cell based on the number of alive neighbors.
+// NextGeneration calculates the next generation of the cell based on the number of alive neighbors
+// using Conway's rule (B3/S23).
 func (c *Cell) NextGeneration(aliveNeighbors int) *Cell {
-	if c.canBeReproduced(aliveNeighbors) {
-		return Alive
-	}
-
-	if c.isUnderpopulated(aliveNeighbors) {
-		return Dead
-	}
-
-	if c.isOverpopulated(aliveNeighbors) {
-		return Dead
-	}
-
-	return c
-}
-
-// canBeReproduced returns true if the dead cell has exactly three alive neighbors.
-func (c *Cell) canBeReproduced(aliveNeighbors int) bool {
-	return c == Dead && aliveNeighbors == 3
+	return c.NextGenerationWithRule(rule.Conway, aliveNeighbors)
 }
 
-// isUnderpopulated returns true if an alive cell has fewer than two alive neighbors.
-func (c *Cell) isUnderpopulated(aliveNeighbors int) bool {
-	return aliveNeighbors < 2
-}
+// NextGenerationWithRule calculates the next generation of the cell based on the number of alive neighbors
+// using the given rule.
+func (c *Cell) NextGenerationWithRule(r rule.Rule, aliveNeighbors int) *Cell {
+	if r.Next(c == Alive, aliveNeighbors) {
+		return Alive
+	}
 
-// isOverpopulated returns true if an alive cell has more than three alive neighbors.
-func (c *Cell) isOverpopulated(aliveNeighbors int) bool {
-	return aliveNeighbors > 3
+	return Dead
 }
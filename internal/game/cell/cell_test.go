@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
 )
 
 func TestCell_NextGeneration(t *testing.T) {
@@ -71,3 +72,48 @@ func TestCell_NextGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestCell_NextGenerationWithRule(t *testing.T) {
+	tt := []struct {
+		name           string
+		rule           rule.Rule
+		aliveNeighbors int
+		cell           *cell.Cell
+		expected       *cell.Cell
+	}{
+		{
+			name:           "the dead cell should be alive if there are six alive neighbors in HighLife",
+			rule:           rule.HighLife,
+			aliveNeighbors: 6,
+			cell:           cell.Dead,
+			expected:       cell.Alive,
+		},
+		{
+			name:           "the dead cell should be dead if there are six alive neighbors in Conway's rule",
+			rule:           rule.Conway,
+			aliveNeighbors: 6,
+			cell:           cell.Dead,
+			expected:       cell.Dead,
+		},
+		{
+			name:           "the alive cell should be dead if there are two alive neighbors in Seeds",
+			rule:           rule.Seeds,
+			aliveNeighbors: 2,
+			cell:           cell.Alive,
+			expected:       cell.Dead,
+		},
+		{
+			name:           "the alive cell should be alive if there are six alive neighbors in Day & Night",
+			rule:           rule.DayAndNight,
+			aliveNeighbors: 6,
+			cell:           cell.Alive,
+			expected:       cell.Alive,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.cell.NextGenerationWithRule(tc.rule, tc.aliveNeighbors))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ivanlemeshev/gameoflife/internal/game/census"
+	"github.com/ivanlemeshev/gameoflife/internal/game/library"
+)
+
+// censusColumns are the columns of the census table. The last one takes the
+// rest of the terminal.
+var censusColumns = []table.Column{
+	{Title: "Count", Width: 6},
+	{Title: "Name", Width: 22},
+	{Title: "Kind", Width: 12},
+	{Title: "Period", Width: 6},
+	{Title: "Cells", Width: 5},
+	{Title: "apgcode", Width: 20},
+}
+
+// openCensus counts the objects of the cells and shows the table of them
+// instead of the cells.
+func (g *Game) openCensus() {
+	g.census = census.Take(g.universe, census.WithDistance(g.censusDistance), census.WithNames(g.objectNames()))
+
+	rows := make([]table.Row, len(g.census.Rows))
+	for i, r := range g.census.Rows {
+		period := ""
+		if r.Period > 0 {
+			period = strconv.Itoa(r.Period)
+		}
+
+		rows[i] = table.Row{strconv.Itoa(r.Count), r.Name, r.Kind.String(), period, strconv.Itoa(r.Population), r.Code}
+	}
+
+	t := table.New(table.WithColumns(censusColumns), table.WithRows(rows), table.WithFocused(true))
+
+	g.censusTable = &t
+	g.resizeCensus()
+	g.status = fmt.Sprintf("Counted %d objects of %d kinds.", g.census.Objects, len(g.census.Rows))
+}
+
+// objectNames returns the names of the periodic patterns of the library by
+// their apgcodes under the current rule.
+func (g *Game) objectNames() map[string]string {
+	names := make(map[string]string)
+
+	entries, err := library.Entries()
+	if err != nil {
+		return names
+	}
+
+	for _, e := range entries {
+		if e.Period == 0 {
+			continue
+		}
+
+		c := census.Classify(e.Pattern.Cells, g.universe.Rule())
+		if _, ok := names[c.Code]; !ok && c.Kind != census.Unclassified {
+			names[c.Code] = e.Pattern.Name
+		}
+	}
+
+	return names
+}
+
+// handleCensusKey scrolls the census table, saves it or closes it.
+func (g *Game) handleCensusKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, g.keys.Census), msg.Type == tea.KeyEsc:
+		g.censusTable = nil
+		return g, nil
+	case key.Matches(msg, g.keys.Save):
+		g.saveCensus()
+		return g, nil
+	case key.Matches(msg, g.keys.Quit):
+		return g, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	*g.censusTable, cmd = g.censusTable.Update(msg)
+
+	return g, cmd
+}
+
+// saveCensus writes the census table to the census file as CSV.
+func (g *Game) saveCensus() {
+	if g.censusPath == "" {
+		g.status = "The file to save the census is not set."
+		return
+	}
+
+	f, err := os.OpenFile(filepath.Clean(g.censusPath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		g.status = fmt.Sprintf("Failed to save the census: %v", err)
+		return
+	}
+
+	err = g.census.WriteCSV(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		g.status = fmt.Sprintf("Failed to save the census: %v", err)
+		return
+	}
+
+	g.status = fmt.Sprintf("Saved the census to %s.", g.censusPath)
+}
+
+// resizeCensus fits the census table into the area of the cells and the
+// timeline.
+func (g *Game) resizeCensus() {
+	if g.censusTable == nil {
+		return
+	}
+
+	width, height := defaultBrowserWidth, defaultBrowserHeight
+	if g.terminalWidth > 0 && g.terminalHeight > 0 {
+		width = g.terminalWidth
+		height = max(g.terminalHeight-len(header)-footerHeight+1, 2)
+	}
+
+	// The codes take the rest of the row after the other columns and the
+	// padding of the cells.
+	columns := make([]table.Column, len(censusColumns))
+	copy(columns, censusColumns)
+
+	rest := width
+	for _, c := range columns[:len(columns)-1] {
+		rest -= c.Width + 2
+	}
+
+	columns[len(columns)-1].Width = max(rest-2, censusColumns[len(censusColumns)-1].Width)
+
+	g.censusTable.SetColumns(columns)
+	g.censusTable.SetWidth(width)
+	g.censusTable.SetHeight(height)
+}
+
+// renderCensus renders the census table.
+func (g *Game) renderCensus(sb *strings.Builder) {
+	sb.WriteString(g.censusTable.View())
+	sb.WriteString("\n")
+}
@@ -0,0 +1,240 @@
+package census
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/period"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+const (
+	// maxPeriod is the longest period of the objects that are classified.
+	maxPeriod = 64
+	// stripHeight is the number of rows encoded by one character of the
+	// extended Wechsler format.
+	stripHeight = 5
+	// digits encode the columns of a strip, and the number of the zero
+	// columns after 'y'.
+	digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+// Kind is the kind of the object by how it evolves in isolation.
+type Kind int
+
+const (
+	// Unclassified objects do not return to their initial state, for
+	// example the objects that are still evolving or that die.
+	Unclassified Kind = iota
+	// StillLife does not change.
+	StillLife
+	// Oscillator returns to its initial state after the period.
+	Oscillator
+	// Spaceship returns to its initial state after the period, but moved.
+	Spaceship
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Unclassified:
+		return "Unclassified"
+	case StillLife:
+		return "Still life"
+	case Oscillator:
+		return "Oscillator"
+	case Spaceship:
+		return "Spaceship"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Classification describes how the object evolves in isolation.
+type Classification struct {
+	// Kind is the kind of the object.
+	Kind Kind
+	// Period is the number of generations after which the object repeats
+	// itself. It is zero for the unclassified objects.
+	Period int
+	// Code is the apgcode of the object, like "xs4_33" for the block. It is
+	// the same for all phases and orientations of the object. The
+	// unclassified objects get their canonical form without the prefix.
+	Code string
+}
+
+// Classify runs the object alone under the rule and names it by the way it
+// repeats itself.
+func Classify(cells []engine.Point, r rule.Rule) Classification {
+	// The unbounded universe does not support B0, and the objects of such
+	// rules do not exist in isolation anyway.
+	if len(cells) == 0 || r.Born(0) {
+		return Classification{Code: Canonical(cells)}
+	}
+
+	u := sparse.New(sparse.WithRule(r))
+	for _, p := range cells {
+		u.SetCell(p.X, p.Y, cell.Alive)
+	}
+
+	d := period.NewDetector(maxPeriod)
+	phases := make([][]engine.Point, 0, maxPeriod)
+
+	for range maxPeriod + 1 {
+		result, ok := d.Observe(u)
+		if ok {
+			// The object must repeat its initial state, otherwise it is
+			// only becoming another object.
+			if result.Generation != 0 {
+				break
+			}
+
+			return classification(result, phases)
+		}
+
+		phases = append(phases, engine.AliveCells(u))
+		u.NextGeneration()
+	}
+
+	return Classification{Code: Canonical(cells)}
+}
+
+// classification returns the code of the periodic object, which is the
+// smallest canonical form of its phases.
+func classification(result period.Result, phases [][]engine.Point) Classification {
+	code := ""
+	for _, phase := range phases[:result.Period] {
+		code = smaller(code, Canonical(phase))
+	}
+
+	switch {
+	case result.Moving():
+		return Classification{Kind: Spaceship, Period: result.Period, Code: fmt.Sprintf("xq%d_%s", result.Period, code)}
+	case result.Static():
+		return Classification{Kind: StillLife, Period: 1, Code: fmt.Sprintf("xs%d_%s", len(phases[0]), code)}
+	default:
+		return Classification{Kind: Oscillator, Period: result.Period, Code: fmt.Sprintf("xp%d_%s", result.Period, code)}
+	}
+}
+
+// Canonical returns the form of the cells in the extended Wechsler format
+// that is the same for all their rotations and reflections: the shortest one,
+// and the first one in the alphabetical order among the shortest.
+func Canonical(cells []engine.Point) string {
+	transforms := []func(p engine.Point) engine.Point{
+		func(p engine.Point) engine.Point { return p },
+		func(p engine.Point) engine.Point { return engine.Point{X: -p.Y, Y: p.X} },
+		func(p engine.Point) engine.Point { return engine.Point{X: -p.X, Y: -p.Y} },
+		func(p engine.Point) engine.Point { return engine.Point{X: p.Y, Y: -p.X} },
+		func(p engine.Point) engine.Point { return engine.Point{X: -p.X, Y: p.Y} },
+		func(p engine.Point) engine.Point { return engine.Point{X: p.X, Y: -p.Y} },
+		func(p engine.Point) engine.Point { return engine.Point{X: p.Y, Y: p.X} },
+		func(p engine.Point) engine.Point { return engine.Point{X: -p.Y, Y: -p.X} },
+	}
+
+	code := ""
+
+	transformed := make([]engine.Point, len(cells))
+	for _, transform := range transforms {
+		for i, p := range cells {
+			transformed[i] = transform(p)
+		}
+
+		code = smaller(code, Wechsler(transformed))
+	}
+
+	return code
+}
+
+// Wechsler returns the cells in the extended Wechsler format relative to
+// their top left corner. The rows are split into strips of five, and every
+// column of a strip is encoded by a character of its alive cells. The strips
+// are separated by 'z', and the runs of the empty columns are shortened with
+// 'w', 'x' and 'y'.
+func Wechsler(cells []engine.Point) string {
+	if len(cells) == 0 {
+		return "0"
+	}
+
+	minX := slices.MinFunc(cells, func(a, b engine.Point) int { return cmp.Compare(a.X, b.X) }).X
+	minY := slices.MinFunc(cells, func(a, b engine.Point) int { return cmp.Compare(a.Y, b.Y) }).Y
+	maxX := slices.MaxFunc(cells, func(a, b engine.Point) int { return cmp.Compare(a.X, b.X) }).X
+	maxY := slices.MaxFunc(cells, func(a, b engine.Point) int { return cmp.Compare(a.Y, b.Y) }).Y
+
+	strips := make([][]int, (maxY-minY)/stripHeight+1)
+	for i := range strips {
+		strips[i] = make([]int, maxX-minX+1)
+	}
+
+	for _, p := range cells {
+		y := p.Y - minY
+		strips[y/stripHeight][p.X-minX] |= 1 << (y % stripHeight)
+	}
+
+	var sb strings.Builder
+
+	for i, columns := range strips {
+		if i > 0 {
+			sb.WriteByte('z')
+		}
+
+		// The empty columns at the end of the strip are omitted.
+		for len(columns) > 0 && columns[len(columns)-1] == 0 {
+			columns = columns[:len(columns)-1]
+		}
+
+		zeros := 0
+
+		for _, column := range columns {
+			if column == 0 {
+				zeros++
+				continue
+			}
+
+			writeZeros(&sb, zeros)
+			zeros = 0
+
+			sb.WriteByte(digits[column])
+		}
+	}
+
+	return sb.String()
+}
+
+// writeZeros writes the run of the empty columns: 'w' for two, 'x' for three
+// and 'y' followed by the digit for four to thirty-nine.
+func writeZeros(sb *strings.Builder, n int) {
+	for n > 0 {
+		switch {
+		case n >= 4:
+			run := min(n, len(digits)+3)
+			sb.WriteByte('y')
+			sb.WriteByte(digits[run-4])
+			n -= run
+		case n == 3:
+			sb.WriteByte('x')
+			n = 0
+		case n == 2:
+			sb.WriteByte('w')
+			n = 0
+		default:
+			sb.WriteByte('0')
+			n = 0
+		}
+	}
+}
+
+// smaller returns the shorter of the codes, or the first one in the
+// alphabetical order if they have the same length. An empty code is ignored.
+func smaller(a, b string) string {
+	if a == "" || len(b) < len(a) || len(b) == len(a) && b < a {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,168 @@
+// Package census splits the alive cells into separate objects and counts
+// them by their kind, like the blocks, blinkers and gliders left after a soup
+// settles.
+package census
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+// DefaultDistance is the distance within which the cells belong to the same
+// object by default. It keeps the spaceships like the lightweight spaceship
+// together.
+const DefaultDistance = 2
+
+// Row is a kind of objects found in the census.
+type Row struct {
+	Classification
+	// Name is the name of the object from the names of the census, or its
+	// code if the name is not known.
+	Name string
+	// Population is the number of alive cells of one object.
+	Population int
+	// Count is the number of objects of this kind.
+	Count int
+}
+
+// Census is the number of objects of each kind, from the most common kind.
+type Census struct {
+	// Rows are the kinds of the objects.
+	Rows []Row
+	// Objects is the number of all objects.
+	Objects int
+}
+
+// Option configures the census.
+type Option func(*options)
+
+type options struct {
+	distance int
+	names    map[string]string
+}
+
+// WithDistance sets the largest distance between two cells of the same
+// object, counted in the columns or rows, whichever is larger. The distance of
+// one joins only the neighbors. By default, DefaultDistance is used.
+func WithDistance(distance int) Option {
+	return func(o *options) {
+		o.distance = max(distance, 1)
+	}
+}
+
+// WithNames sets the names of the objects by their apgcodes.
+func WithNames(names map[string]string) Option {
+	return func(o *options) {
+		o.names = names
+	}
+}
+
+// Take splits the alive cells of the engine into objects and classifies each
+// of them under the rule of the engine. The objects are classified as if
+// nothing else was around them.
+func Take(e engine.Engine, opts ...Option) Census {
+	o := options{distance: DefaultDistance}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	objects := Objects(engine.AliveCells(e), o.distance)
+
+	rows := make(map[string]*Row)
+
+	for _, object := range objects {
+		classification := Classify(object, e.Rule())
+
+		row, ok := rows[classification.Code]
+		if !ok {
+			name, ok := o.names[classification.Code]
+			if !ok {
+				name = classification.Code
+			}
+
+			row = &Row{Classification: classification, Name: name, Population: len(object)}
+			rows[classification.Code] = row
+		}
+
+		row.Count++
+	}
+
+	c := Census{Rows: make([]Row, 0, len(rows)), Objects: len(objects)}
+	for _, row := range rows {
+		c.Rows = append(c.Rows, *row)
+	}
+
+	slices.SortFunc(c.Rows, func(a, b Row) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Code, b.Code))
+	})
+
+	return c
+}
+
+// Objects splits the cells into groups in which every cell is within the
+// distance of another cell of the group. The groups are ordered by their
+// first cell in the order of the cells.
+func Objects(cells []engine.Point, distance int) [][]engine.Point {
+	unvisited := make(map[engine.Point]struct{}, len(cells))
+	for _, p := range cells {
+		unvisited[p] = struct{}{}
+	}
+
+	var objects [][]engine.Point
+
+	for _, p := range cells {
+		if _, ok := unvisited[p]; !ok {
+			continue
+		}
+
+		delete(unvisited, p)
+
+		// Collect the cells close to the collected ones until there are no
+		// more of them.
+		object := []engine.Point{p}
+		for i := 0; i < len(object); i++ {
+			for dy := -distance; dy <= distance; dy++ {
+				for dx := -distance; dx <= distance; dx++ {
+					q := engine.Point{X: object[i].X + dx, Y: object[i].Y + dy}
+					if _, ok := unvisited[q]; ok {
+						delete(unvisited, q)
+						object = append(object, q)
+					}
+				}
+			}
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects
+}
+
+// WriteCSV writes the census as a table with a header.
+func (c Census) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	records := [][]string{{"count", "name", "kind", "period", "population", "apgcode"}}
+	for _, row := range c.Rows {
+		records = append(records, []string{
+			strconv.Itoa(row.Count),
+			row.Name,
+			row.Kind.String(),
+			strconv.Itoa(row.Period),
+			strconv.Itoa(row.Population),
+			row.Code,
+		})
+	}
+
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("write census: %w", err)
+	}
+
+	return nil
+}
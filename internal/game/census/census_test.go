@@ -0,0 +1,189 @@
+package census_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/census"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/library"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+func TestClassify(t *testing.T) {
+	expected := map[string]census.Classification{
+		"Block":                  {Kind: census.StillLife, Period: 1, Code: "xs4_33"},
+		"Beehive":                {Kind: census.StillLife, Period: 1, Code: "xs6_696"},
+		"Loaf":                   {Kind: census.StillLife, Period: 1, Code: "xs7_2596"},
+		"Boat":                   {Kind: census.StillLife, Period: 1, Code: "xs5_253"},
+		"Tub":                    {Kind: census.StillLife, Period: 1, Code: "xs4_252"},
+		"Blinker":                {Kind: census.Oscillator, Period: 2, Code: "xp2_7"},
+		"Toad":                   {Kind: census.Oscillator, Period: 2, Code: "xp2_7e"},
+		"Beacon":                 {Kind: census.Oscillator, Period: 2, Code: "xp2_318c"},
+		"Pulsar":                 {Kind: census.Oscillator, Period: 3, Code: "xp3_co9nas0san9oczgoldlo0oldlogz1047210127401"},
+		"Pentadecathlon":         {Kind: census.Oscillator, Period: 15, Code: "xp15_4r4z4r4"},
+		"Glider":                 {Kind: census.Spaceship, Period: 4, Code: "xq4_153"},
+		"Lightweight spaceship":  {Kind: census.Spaceship, Period: 4, Code: "xq4_6frc"},
+		"Middleweight spaceship": {Kind: census.Spaceship, Period: 4, Code: "xq4_27dee6"},
+		"Heavyweight spaceship":  {Kind: census.Spaceship, Period: 4, Code: "xq4_27deee6"},
+	}
+
+	entries, err := library.Entries()
+	require.NoError(t, err)
+
+	for _, e := range entries {
+		t.Run(e.Pattern.Name, func(t *testing.T) {
+			c := census.Classify(e.Pattern.Cells, rule.Conway)
+
+			if want, ok := expected[e.Pattern.Name]; ok {
+				assert.Equal(t, want, c)
+				return
+			}
+
+			// The guns, puffers and methuselahs do not repeat themselves.
+			assert.Equal(t, census.Unclassified, c.Kind)
+			assert.Equal(t, census.Canonical(e.Pattern.Cells), c.Code)
+		})
+	}
+}
+
+func TestWechsler(t *testing.T) {
+	tt := []struct {
+		name     string
+		cells    []engine.Point
+		expected string
+	}{
+		{
+			name:     "no cells",
+			expected: "0",
+		},
+		{
+			name:     "empty columns",
+			cells:    []engine.Point{{X: 10, Y: 10}, {X: 12, Y: 10}, {X: 15, Y: 10}, {X: 19, Y: 10}, {X: 24, Y: 10}},
+			expected: "101w1x1y01",
+		},
+		{
+			name:     "strips",
+			cells:    []engine.Point{{X: 0, Y: 0}, {X: 2, Y: 5}, {X: 0, Y: 14}},
+			expected: "1zw1zg",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, census.Wechsler(tc.cells))
+		})
+	}
+}
+
+func TestObjects(t *testing.T) {
+	// Two blocks one column apart and a cell three columns away.
+	cells := []engine.Point{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0},
+		{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}, {X: 8, Y: 1},
+	}
+
+	tt := []struct {
+		distance int
+		expected [][]engine.Point
+	}{
+		{
+			distance: 1,
+			expected: [][]engine.Point{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+				{{X: 3, Y: 0}, {X: 4, Y: 0}, {X: 3, Y: 1}, {X: 4, Y: 1}},
+				{{X: 8, Y: 1}},
+			},
+		},
+		{
+			distance: 2,
+			expected: [][]engine.Point{
+				{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}},
+				{{X: 8, Y: 1}},
+			},
+		},
+		{
+			distance: 4,
+			expected: [][]engine.Point{cells},
+		},
+	}
+
+	for _, tc := range tt {
+		objects := census.Objects(cells, tc.distance)
+		require.Len(t, objects, len(tc.expected))
+
+		for i := range objects {
+			assert.ElementsMatch(t, tc.expected[i], objects[i])
+		}
+	}
+}
+
+func TestTake(t *testing.T) {
+	u := sparse.New()
+
+	place := func(x, y int, cells ...engine.Point) {
+		for _, p := range cells {
+			u.SetCell(x+p.X, y+p.Y, cell.Alive)
+		}
+	}
+
+	block := []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	blinker := []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}
+	glider := []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}
+
+	// The objects are counted in all phases and orientations.
+	place(0, 0, block...)
+	place(10, 0, block...)
+	place(20, 0, blinker...)
+	place(30, 0, engine.Point{X: 0, Y: 0}, engine.Point{X: 0, Y: 1}, engine.Point{X: 0, Y: 2})
+	place(40, 0, blinker...)
+	place(0, 10, glider...)
+	place(10, 10, engine.Point{X: 0, Y: 0}, engine.Point{X: 1, Y: 0})
+
+	c := census.Take(u, census.WithNames(map[string]string{"xs4_33": "Block", "xp2_7": "Blinker"}))
+	assert.Equal(t, 7, c.Objects)
+	assert.Equal(t, []census.Row{
+		{
+			Classification: census.Classification{Kind: census.Oscillator, Period: 2, Code: "xp2_7"},
+			Name:           "Blinker",
+			Population:     3,
+			Count:          3,
+		},
+		{
+			Classification: census.Classification{Kind: census.StillLife, Period: 1, Code: "xs4_33"},
+			Name:           "Block",
+			Population:     4,
+			Count:          2,
+		},
+		{
+			Classification: census.Classification{Code: "3"},
+			Name:           "3",
+			Population:     2,
+			Count:          1,
+		},
+		{
+			Classification: census.Classification{Kind: census.Spaceship, Period: 4, Code: "xq4_153"},
+			Name:           "xq4_153",
+			Population:     5,
+			Count:          1,
+		},
+	}, c.Rows)
+
+	// All objects are joined within the larger distance.
+	c = census.Take(u, census.WithDistance(10))
+	assert.Equal(t, 1, c.Objects)
+
+	var buf bytes.Buffer
+	require.NoError(t, census.Take(u).WriteCSV(&buf))
+	assert.Equal(t, `count,name,kind,period,population,apgcode
+3,xp2_7,Oscillator,2,3,xp2_7
+2,xs4_33,Still life,1,4,xs4_33
+1,3,Unclassified,0,2,3
+1,xq4_153,Spaceship,4,5,xq4_153
+`, buf.String())
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+// maxCount is the largest number of cells the cursor is moved by at once.
+const maxCount = 9999
+
+// pen is how the cursor changes the cells it moves over.
+type pen int
+
+const (
+	// penUp does not change the cells.
+	penUp pen = iota
+	// penDraw makes the cells alive.
+	penDraw
+	// penErase makes the cells dead.
+	penErase
+)
+
+// state returns the state the cells are set to by the pen.
+func (p pen) state() *cell.Cell {
+	if p == penDraw {
+		return cell.Alive
+	}
+
+	return cell.Dead
+}
+
+// cursor is the keyboard cursor that edits the cells without the mouse.
+type cursor struct {
+	// position is the cell under the cursor.
+	position engine.Point
+	// pen is how the cursor changes the cells it moves over.
+	pen pen
+	// count is the number of cells the next movement moves the cursor by.
+	// Zero means one cell.
+	count int
+}
+
+// toggleCursor shows the cursor in the center of the viewport, or hides it.
+func (g *Game) toggleCursor() {
+	if g.cursor != nil {
+		g.cursor = nil
+		return
+	}
+
+	g.cursor = &cursor{position: g.clampToBoard(g.viewport.Center())}
+}
+
+// handleCursorKey moves the cursor and edits the cells under it. It returns
+// false if the key is not about the cursor. The count is reset by the other
+// keys.
+func (g *Game) handleCursorKey(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, g.keys.Count):
+		g.addCountDigit(int(msg.String()[0] - '0'))
+	case key.Matches(msg, g.keys.Up):
+		g.moveCursor(0, -1)
+	case key.Matches(msg, g.keys.Down):
+		g.moveCursor(0, 1)
+	case key.Matches(msg, g.keys.Left):
+		g.moveCursor(-1, 0)
+	case key.Matches(msg, g.keys.Right):
+		g.moveCursor(1, 0)
+	case key.Matches(msg, g.keys.ToggleCell) && g.pasting:
+		g.stamp()
+	case key.Matches(msg, g.keys.ToggleCell):
+		g.toggleCursorCell()
+	case key.Matches(msg, g.keys.Draw):
+		g.setPen(penDraw)
+	case key.Matches(msg, g.keys.Erase):
+		g.setPen(penErase)
+	default:
+		g.cursor.count = 0
+		return false
+	}
+
+	return true
+}
+
+// moveCursor moves the cursor by the count of cells in the given direction.
+// The cursor stays inside the bounded engines, and the viewport, the
+// selection and the ghost of the clipboard follow the cursor. If the pen is
+// down, the cells the cursor passes over are set.
+func (g *Game) moveCursor(dx, dy int) {
+	n := max(g.cursor.count, 1)
+	g.cursor.count = 0
+
+	from := g.cursor.position
+	to := g.clampToBoard(engine.Point{X: from.X + dx*n, Y: from.Y + dy*n})
+	g.cursor.position = to
+	g.followCursor(to)
+
+	if g.cursor.pen != penUp && !g.started {
+		for _, p := range engine.Line(from, to) {
+			g.universe.SetCell(p.X, p.Y, g.cursor.pen.state())
+		}
+
+		g.record()
+	}
+
+	if !g.viewport.Bounds().Contains(to.X, to.Y) {
+		g.viewport.CenterOn(to)
+	}
+}
+
+// addCountDigit appends the digit to the count of cells the next movement
+// moves the cursor by.
+func (g *Game) addCountDigit(digit int) {
+	g.cursor.count = min(g.cursor.count*10+digit, maxCount)
+}
+
+// toggleCursorCell toggles the cell under the cursor.
+func (g *Game) toggleCursorCell() {
+	if g.started {
+		return
+	}
+
+	engine.Toggle(g.universe, g.cursor.position.X, g.cursor.position.Y)
+	g.record()
+}
+
+// setPen puts the pen down, so the cell under the cursor and the cells the
+// cursor moves over are changed by the pen. The pen is lifted if it is
+// already down.
+func (g *Game) setPen(p pen) {
+	if g.cursor.pen == p {
+		g.cursor.pen = penUp
+		return
+	}
+
+	g.cursor.pen = p
+
+	if !g.started {
+		g.universe.SetCell(g.cursor.position.X, g.cursor.position.Y, p.state())
+		g.record()
+	}
+}
+
+// cursorStatus returns the position of the cursor, the state of the pen and
+// the count for the footer.
+func (g *Game) cursorStatus() string {
+	status := fmt.Sprintf(" | Cursor: %d,%d", g.cursor.position.X, g.cursor.position.Y)
+
+	switch g.cursor.pen {
+	case penDraw:
+		status += " draw"
+	case penErase:
+		status += " erase"
+	}
+
+	if g.cursor.count > 0 {
+		status += fmt.Sprintf(" %d", g.cursor.count)
+	}
+
+	return status
+}
+
+// clampToBoard returns the nearest cell of the bounded engine. The cells of
+// the unbounded engines are returned as they are.
+func (g *Game) clampToBoard(p engine.Point) engine.Point {
+	board, bounded := g.board()
+	if !bounded || board.Empty() {
+		return p
+	}
+
+	return engine.Point{
+		X: min(max(p.X, board.MinX), board.MaxX-1),
+		Y: min(max(p.Y, board.MinY), board.MaxY-1),
+	}
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+)
+
+// maxPeriod is the longest period of the cells that is detected.
+const maxPeriod = 1000
+
+// detect reports when the cells become static or periodic, and pauses the
+// game if the auto-pause is on. The moving cells on a board with edges do not
+// pause the game, because they are going to hit the edge.
+func (g *Game) detect() {
+	result, ok := g.detector.Observe(g.universe)
+	if !ok {
+		return
+	}
+
+	g.status = fmt.Sprintf("Stabilised at generation %d with period %d.", result.Generation, result.Period)
+	if result.Moving() {
+		g.status = fmt.Sprintf(
+			"Stabilised at generation %d with period %d, moving at %s.",
+			result.Generation, result.Period, result.Speed(),
+		)
+	}
+
+	if g.autoPause && g.started && (!result.Moving() || !g.walled()) {
+		g.pause()
+	}
+}
+
+// walled reports whether the cells are stopped by the edges of the board: the
+// engine is bounded, and its edges are not connected like a torus.
+func (g *Game) walled() bool {
+	if _, bounded := g.board(); !bounded {
+		return false
+	}
+
+	sg, ok := g.universe.(*grid.Grid)
+
+	return !ok || sg.Topology() != grid.Torus
+}
+
+// toggleAutoPause switches whether the game is paused when the cells become
+// static or periodic.
+func (g *Game) toggleAutoPause() {
+	g.autoPause = !g.autoPause
+
+	if g.autoPause {
+		g.status = "The game is paused when the cells become static or periodic."
+		return
+	}
+
+	g.status = "The game is not paused when the cells become static or periodic."
+}
+
+// periodStatus returns the period and the speed of the cells and the
+// auto-pause for the footer.
+func (g *Game) periodStatus() string {
+	status := ""
+	if result, ok := g.detector.Result(); ok {
+		status = fmt.Sprintf(" | Period: %d", result.Period)
+
+		if result.Moving() {
+			status += " | Speed: " + result.Speed()
+		}
+	}
+
+	if g.autoPause {
+		status += " | Auto-pause"
+	}
+
+	return status
+}
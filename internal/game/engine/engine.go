@@ -0,0 +1,312 @@
+package engine
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+// Engine calculates the generations of cells. The game works with any engine,
+// so the cells can be stored in a bounded grid or in an unbounded universe.
+type Engine interface {
+	// Rule returns the rule used to calculate the next generation of the cells.
+	Rule() rule.Rule
+	// Generation returns the current generation of the cells.
+	Generation() int
+	// NextGeneration moves the cells to the next generation.
+	NextGeneration()
+	// Cell returns the cell in the x-th column and y-th row.
+	Cell(x, y int) *cell.Cell
+	// SetCell sets the cell in the x-th column and y-th row.
+	SetCell(x, y int, c *cell.Cell)
+	// BoundingBox returns the smallest rectangle that contains all alive cells.
+	BoundingBox() Rect
+}
+
+// Bounded is implemented by the engines with a finite number of columns and
+// rows. The columns and rows of the cells start from zero.
+type Bounded interface {
+	Engine
+	// Width returns the number of columns.
+	Width() int
+	// Height returns the number of rows.
+	Height() int
+}
+
+// Resizable is implemented by the bounded engines that can change their size.
+type Resizable interface {
+	Bounded
+	// Resize changes the number of columns and rows keeping the generation
+	// and the cells inside the new size.
+	Resize(width, height int)
+}
+
+// Advancer is implemented by the engines that can move the cells many
+// generations forward faster than one generation at a time.
+type Advancer interface {
+	Engine
+	// Advance moves the cells the given number of generations forward.
+	Advance(generations int)
+}
+
+// Rewindable is implemented by the engines whose generation can be set, so
+// the cells can be moved back to a recorded state.
+type Rewindable interface {
+	Engine
+	// SetGeneration sets the current generation without changing the cells.
+	SetGeneration(generation int)
+}
+
+// Populated is implemented by the engines that keep the number of alive
+// cells, so the cells do not have to be scanned to count them.
+type Populated interface {
+	Engine
+	// Population returns the number of alive cells.
+	Population() int
+}
+
+// Counter is implemented by the engines that count the changed cells while
+// they calculate the next generation.
+type Counter interface {
+	Populated
+	// Changes returns the number of cells born and the number of cells died
+	// in the last generation. It returns false if the cells or the generation
+	// are changed since the last generation was calculated.
+	Changes() (births, deaths int, ok bool)
+}
+
+// Point represents the x-th column and y-th row.
+type Point struct {
+	X int
+	Y int
+}
+
+// Rect represents a rectangle of cells. The minimum column and row are
+// inclusive, and the maximum column and row are exclusive.
+type Rect struct {
+	MinX int
+	MinY int
+	MaxX int
+	MaxY int
+}
+
+// Width returns the number of columns in the rectangle.
+func (r Rect) Width() int {
+	return max(r.MaxX-r.MinX, 0)
+}
+
+// Height returns the number of rows in the rectangle.
+func (r Rect) Height() int {
+	return max(r.MaxY-r.MinY, 0)
+}
+
+// Empty returns true if the rectangle contains no cells.
+func (r Rect) Empty() bool {
+	return r.Width() == 0 || r.Height() == 0
+}
+
+// Contains returns true if the cell in the x-th column and y-th row is inside the rectangle.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.MinX && x < r.MaxX && y >= r.MinY && y < r.MaxY
+}
+
+// Border returns true if the cell in the x-th column and y-th row is inside
+// the rectangle on its first or last column or row, so the rectangle may
+// shrink without it.
+func (r Rect) Border(x, y int) bool {
+	return r.Contains(x, y) && (x == r.MinX || x == r.MaxX-1 || y == r.MinY || y == r.MaxY-1)
+}
+
+// Union returns the smallest rectangle that contains both rectangles.
+func (r Rect) Union(other Rect) Rect {
+	if r.Empty() {
+		return other
+	}
+
+	if other.Empty() {
+		return r
+	}
+
+	return Rect{
+		MinX: min(r.MinX, other.MinX),
+		MinY: min(r.MinY, other.MinY),
+		MaxX: max(r.MaxX, other.MaxX),
+		MaxY: max(r.MaxY, other.MaxY),
+	}
+}
+
+// Intersect returns the largest rectangle contained by both rectangles.
+func (r Rect) Intersect(other Rect) Rect {
+	intersection := Rect{
+		MinX: max(r.MinX, other.MinX),
+		MinY: max(r.MinY, other.MinY),
+		MaxX: min(r.MaxX, other.MaxX),
+		MaxY: min(r.MaxY, other.MaxY),
+	}
+
+	if intersection.Empty() {
+		return Rect{}
+	}
+
+	return intersection
+}
+
+// String returns the rectangle as its size and the offsets of its top left
+// cell, like "20x8+10-5", which is parsed back by ParseRect.
+func (r Rect) String() string {
+	return fmt.Sprintf("%dx%d%+d%+d", r.Width(), r.Height(), r.MinX, r.MinY)
+}
+
+// ParseRect parses the rectangle in the format returned by Rect.String, the
+// size followed by the signed column and row of its top left cell.
+func ParseRect(s string) (Rect, error) {
+	size, offsets, ok := strings.Cut(strings.TrimSpace(s), "x")
+
+	// The offsets start at the first and the second sign after the height.
+	first := strings.IndexAny(offsets, "+-")
+	second := -1
+	if first >= 0 {
+		second = strings.IndexAny(offsets[first+1:], "+-")
+	}
+
+	if !ok || first < 0 || second < 0 {
+		return Rect{}, fmt.Errorf("invalid rectangle %q: expected WIDTHxHEIGHT+X+Y", s)
+	}
+
+	second += first + 1
+
+	values := make([]int, 0, 4)
+	for _, part := range []string{size, offsets[:first], offsets[first:second], offsets[second:]} {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return Rect{}, fmt.Errorf("invalid rectangle %q: expected WIDTHxHEIGHT+X+Y", s)
+		}
+
+		values = append(values, v)
+	}
+
+	width, height, x, y := values[0], values[1], values[2], values[3]
+	if width < 1 || height < 1 || strings.ContainsAny(size+offsets[:first], "+-") {
+		return Rect{}, fmt.Errorf("invalid rectangle %q: the size must be positive", s)
+	}
+
+	return Rect{MinX: x, MinY: y, MaxX: x + width, MaxY: y + height}, nil
+}
+
+// Line returns the cells of the straight line from one cell to another one,
+// both included. The neighbor cells of the line touch each other at least by
+// the corners, so the line has no gaps.
+func Line(from, to Point) []Point {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	stepX, stepY := sign(to.X-from.X), sign(to.Y-from.Y)
+
+	points := []Point{from}
+
+	p, e := from, dx+dy
+	for p != to {
+		e2 := 2 * e
+
+		if e2 >= dy {
+			e += dy
+			p.X += stepX
+		}
+
+		if e2 <= dx {
+			e += dx
+			p.Y += stepY
+		}
+
+		points = append(points, p)
+	}
+
+	return points
+}
+
+// Toggle makes the cell alive or dead depending on the current state in the x-th column and y-th row.
+func Toggle(e Engine, x, y int) {
+	if e.Cell(x, y) == cell.Dead {
+		e.SetCell(x, y, cell.Alive)
+		return
+	}
+
+	e.SetCell(x, y, cell.Dead)
+}
+
+// Advance moves the cells the given number of generations forward. The
+// engines that implement Advancer do it at once, and the others generation
+// by generation.
+func Advance(e Engine, generations int) {
+	if a, ok := e.(Advancer); ok {
+		a.Advance(generations)
+		return
+	}
+
+	for range generations {
+		e.NextGeneration()
+	}
+}
+
+// AliveCells returns the alive cells ordered by rows and then by columns.
+func AliveCells(e Engine) []Point {
+	var points []Point
+
+	bounds := e.BoundingBox()
+	for y := bounds.MinY; y < bounds.MaxY; y++ {
+		for x := bounds.MinX; x < bounds.MaxX; x++ {
+			if e.Cell(x, y) == cell.Alive {
+				points = append(points, Point{X: x, Y: y})
+			}
+		}
+	}
+
+	return points
+}
+
+// Hash returns the FNV-1a hash of the points in their order, so the same
+// cells listed in the same order get the same hash.
+func Hash(points []Point) uint64 {
+	h := fnv.New64a()
+
+	var buf []byte
+	for _, p := range points {
+		buf = binary.AppendVarint(buf[:0], int64(p.X))
+		buf = binary.AppendVarint(buf, int64(p.Y))
+		_, _ = h.Write(buf)
+	}
+
+	return h.Sum64()
+}
+
+// Population returns the number of alive cells. The cells are scanned only if
+// the engine does not keep their number.
+func Population(e Engine) int {
+	if p, ok := e.(Populated); ok {
+		return p.Population()
+	}
+
+	return len(AliveCells(e))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,233 @@
+package engine_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/hashlife"
+)
+
+func TestRect(t *testing.T) {
+	r := engine.Rect{MinX: -2, MinY: 1, MaxX: 3, MaxY: 4}
+
+	assert.Equal(t, 5, r.Width())
+	assert.Equal(t, 3, r.Height())
+	assert.False(t, r.Empty())
+	assert.True(t, r.Contains(-2, 1))
+	assert.True(t, r.Contains(2, 3))
+	assert.False(t, r.Contains(3, 3))
+	assert.False(t, r.Contains(2, 4))
+	assert.True(t, engine.Rect{MinX: 1, MinY: 1, MaxX: 1, MaxY: 5}.Empty())
+}
+
+func TestParseRect(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected engine.Rect
+		err      bool
+	}{
+		{input: "20x8+10+5", expected: engine.Rect{MinX: 10, MinY: 5, MaxX: 30, MaxY: 13}},
+		{input: "3x4-2-1", expected: engine.Rect{MinX: -2, MinY: -1, MaxX: 1, MaxY: 3}},
+		{input: "1x1+0-7", expected: engine.Rect{MinX: 0, MinY: -7, MaxX: 1, MaxY: -6}},
+		{input: "20x8", err: true},
+		{input: "20x8+10", err: true},
+		{input: "20+10+5", err: true},
+		{input: "0x8+10+5", err: true},
+		{input: "+20x8+10+5", err: true},
+		{input: "20x8+a+5", err: true},
+		{input: "20x8+10+5x", err: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.input, func(t *testing.T) {
+			r, err := engine.ParseRect(tc.input)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, r)
+			assert.Equal(t, tc.input, r.String())
+		})
+	}
+}
+
+func TestRect_Union(t *testing.T) {
+	tt := []struct {
+		name     string
+		a        engine.Rect
+		b        engine.Rect
+		expected engine.Rect
+	}{
+		{
+			name:     "union of two rectangles",
+			a:        engine.Rect{MinX: 0, MinY: 0, MaxX: 2, MaxY: 2},
+			b:        engine.Rect{MinX: -1, MinY: 1, MaxX: 1, MaxY: 5},
+			expected: engine.Rect{MinX: -1, MinY: 0, MaxX: 2, MaxY: 5},
+		},
+		{
+			name:     "union with an empty rectangle",
+			a:        engine.Rect{},
+			b:        engine.Rect{MinX: 3, MinY: 3, MaxX: 4, MaxY: 4},
+			expected: engine.Rect{MinX: 3, MinY: 3, MaxX: 4, MaxY: 4},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.a.Union(tc.b))
+			assert.Equal(t, tc.expected, tc.b.Union(tc.a))
+		})
+	}
+}
+
+func TestRect_Intersect(t *testing.T) {
+	tt := []struct {
+		name     string
+		a        engine.Rect
+		b        engine.Rect
+		expected engine.Rect
+	}{
+		{
+			name:     "overlapping rectangles",
+			a:        engine.Rect{MinX: 0, MinY: 0, MaxX: 2, MaxY: 2},
+			b:        engine.Rect{MinX: -1, MinY: 1, MaxX: 1, MaxY: 5},
+			expected: engine.Rect{MinX: 0, MinY: 1, MaxX: 1, MaxY: 2},
+		},
+		{
+			name:     "rectangle inside another one",
+			a:        engine.Rect{MinX: -5, MinY: -5, MaxX: 5, MaxY: 5},
+			b:        engine.Rect{MinX: 1, MinY: 1, MaxX: 2, MaxY: 3},
+			expected: engine.Rect{MinX: 1, MinY: 1, MaxX: 2, MaxY: 3},
+		},
+		{
+			name:     "touching rectangles",
+			a:        engine.Rect{MinX: 0, MinY: 0, MaxX: 2, MaxY: 2},
+			b:        engine.Rect{MinX: 2, MinY: 0, MaxX: 4, MaxY: 2},
+			expected: engine.Rect{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.a.Intersect(tc.b))
+			assert.Equal(t, tc.expected, tc.b.Intersect(tc.a))
+		})
+	}
+}
+
+func TestLine(t *testing.T) {
+	tt := []struct {
+		name     string
+		from     engine.Point
+		to       engine.Point
+		expected []engine.Point
+	}{
+		{
+			name:     "single cell",
+			from:     engine.Point{X: 1, Y: 1},
+			to:       engine.Point{X: 1, Y: 1},
+			expected: []engine.Point{{X: 1, Y: 1}},
+		},
+		{
+			name:     "horizontal line",
+			from:     engine.Point{X: 2, Y: 0},
+			to:       engine.Point{X: -1, Y: 0},
+			expected: []engine.Point{{X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}, {X: -1, Y: 0}},
+		},
+		{
+			name:     "diagonal line",
+			from:     engine.Point{X: 0, Y: 0},
+			to:       engine.Point{X: 2, Y: -2},
+			expected: []engine.Point{{X: 0, Y: 0}, {X: 1, Y: -1}, {X: 2, Y: -2}},
+		},
+		{
+			name:     "steep line",
+			from:     engine.Point{X: 0, Y: 0},
+			to:       engine.Point{X: 1, Y: 4},
+			expected: []engine.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}, {X: 1, Y: 4}},
+		},
+		{
+			name:     "shallow line",
+			from:     engine.Point{X: 0, Y: 0},
+			to:       engine.Point{X: 6, Y: 3},
+			expected: []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 2}, {X: 4, Y: 2}, {X: 5, Y: 3}, {X: 6, Y: 3}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, engine.Line(tc.from, tc.to))
+		})
+	}
+}
+
+func TestToggle(t *testing.T) {
+	sg := grid.New(3, 3)
+
+	engine.Toggle(sg, 1, 2)
+	assert.Equal(t, cell.Alive, sg.Cell(1, 2))
+
+	engine.Toggle(sg, 1, 2)
+	assert.Equal(t, cell.Dead, sg.Cell(1, 2))
+}
+
+func TestAdvance(t *testing.T) {
+	tt := []struct {
+		name string
+		e    engine.Engine
+	}{
+		{name: "generation by generation", e: grid.New(5, 5)},
+		{name: "advancer", e: hashlife.New()},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			// A blinker is vertical after an odd number of generations.
+			for x := 1; x <= 3; x++ {
+				tc.e.SetCell(x, 2, cell.Alive)
+			}
+
+			engine.Advance(tc.e, 5)
+
+			assert.Equal(t, 5, tc.e.Generation())
+			assert.Equal(t, []engine.Point{{X: 2, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 3}}, engine.AliveCells(tc.e))
+		})
+	}
+}
+
+func TestAliveCells(t *testing.T) {
+	sg := grid.New(3, 3)
+	sg.SetCell(2, 0, cell.Alive)
+	sg.SetCell(0, 1, cell.Alive)
+	sg.SetCell(1, 1, cell.Alive)
+
+	expected := []engine.Point{{X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	assert.Equal(t, expected, engine.AliveCells(sg))
+}
+
+func TestHash(t *testing.T) {
+	cells := []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: -1, Y: 2}}
+
+	assert.Equal(t, engine.Hash(cells), engine.Hash(slices.Clone(cells)))
+	assert.NotEqual(t, engine.Hash(cells), engine.Hash(cells[:2]))
+	assert.NotEqual(t, engine.Hash(cells), engine.Hash([]engine.Point{{X: 1, Y: 0}, {X: 0, Y: 0}, {X: -1, Y: 2}}))
+}
+
+func TestPopulation(t *testing.T) {
+	sg := grid.New(3, 3)
+	sg.SetCell(2, 0, cell.Alive)
+	sg.SetCell(0, 1, cell.Alive)
+
+	hl := hashlife.New()
+	hl.SetCell(-100, 100, cell.Alive)
+
+	assert.Equal(t, 2, engine.Population(sg))
+	assert.Equal(t, 1, engine.Population(hl))
+}
@@ -0,0 +1,38 @@
+package engine
+
+// LeafLevel is the level of the leaves of the quadtree. The leaves are
+// squares of 8x8 cells, as in the Macrocell format.
+const LeafLevel = 3
+
+// Node is a square of 2^level x 2^level cells in a quadtree.
+type Node interface {
+	// Level returns the level of the node.
+	Level() int
+	// Population returns the number of alive cells in the node.
+	Population() int
+}
+
+// Quadtree is implemented by the engines that store the cells in a canonical
+// quadtree. The huge patterns can be built and walked node by node through
+// it, so they never have to be expanded into the list of cells.
+type Quadtree interface {
+	Engine
+	// Leaf returns the node of 8x8 cells. The x-th bit of the y-th row is the
+	// cell in the x-th column and y-th row of the node.
+	Leaf(rows [8]uint8) Node
+	// Join returns the node with the given quadrants of the same level.
+	Join(nw, ne, sw, se Node) Node
+	// EmptyNode returns the node without alive cells at the given level,
+	// which is not less than the leaf level.
+	EmptyNode(level int) Node
+	// Quadrants returns the quadrants of the node above the leaf level.
+	Quadrants(n Node) (nw, ne, sw, se Node)
+	// Rows returns the cells of the leaf node.
+	Rows(n Node) [8]uint8
+	// Root returns the root node. The root is centered around the origin, so
+	// its top left corner is in (-2^(level-1), -2^(level-1)).
+	Root() Node
+	// SetRoot replaces all cells with the node centered around the origin
+	// and sets the current generation.
+	SetRoot(n Node, generation int)
+}
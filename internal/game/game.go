@@ -2,43 +2,344 @@ package game
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ivanlemeshev/gameoflife/internal/game/census"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
 	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/history"
 	"github.com/ivanlemeshev/gameoflife/internal/game/mouse"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/period"
+	"github.com/ivanlemeshev/gameoflife/internal/game/render"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
+	"github.com/ivanlemeshev/gameoflife/internal/game/viewport"
 )
 
-const (
-	speed         = 500 * time.Millisecond
-	setGreenColor = "\033[32m"
-	resetColor    = "\033[0m"
-)
+const defaultTickInterval = 500 * time.Millisecond
+
+// tickMsg moves the cells to the next generation. The id tells which start
+// of the game the tick belongs to, so the ticks scheduled before a pause are
+// dropped after the game is started again.
+type tickMsg struct {
+	id int
+}
 
-type tickMsg time.Time
+// drag is the panning of the viewport with the mouse.
+type drag struct {
+	// x and y are the screen cell where the mouse button is pressed.
+	x int
+	y int
+	// viewport is the viewport when the mouse button is pressed.
+	viewport viewport.Viewport
+}
 
 // Game represents the bubbletea model for the game.
 type Game struct {
-	started bool
-	width   int
-	height  int
-	grid    *grid.Grid
-	spinner spinner.Model
-	keys    keyMap
+	started         bool
+	width           int
+	height          int
+	terminalWidth   int
+	terminalHeight  int
+	fitTerminal     bool
+	placed          bool
+	universe        engine.Engine
+	newEngine       func(width, height int) engine.Engine
+	newQuadtree     func() engine.Engine
+	viewport        viewport.Viewport
+	renderer        render.Renderer
+	drag            *drag
+	paint           *paint
+	cursor          *cursor
+	selecting       bool
+	selection       *selection
+	clipboard       *pattern.Pattern
+	pasting         bool
+	pastePosition   engine.Point
+	systemClipboard io.Writer
+	browser         *list.Model
+	census          census.Census
+	censusTable     *table.Model
+	censusDistance  int
+	censusPath      string
+	history         *history.History
+	timeline        *history.Timeline
+	detector        *period.Detector
+	autoPause       bool
+	showStats       bool
+	showHelp        bool
+	help            help.Model
+	populations     []int
+	pattern         *pattern.Pattern
+	macrocell       *pattern.Macrocell
+	savePath        string
+	interval        time.Duration
+	tickID          int
+	skip            int
+	seed            uint64
+	density         float64
+	soupSize        int
+	soupArea        engine.Rect
+	symmetry        soup.Symmetry
+	lastSoup        *soupFill
+	status          string
+	spinner         spinner.Model
+	keys            keyMap
+}
+
+// Option configures the game.
+type Option func(*Game)
+
+// WithEngine sets the function that creates the engine for the cells. It is
+// called with the current width and height of the game when the game is
+// created and every time the game is reset. By default, the cells are stored
+// in a grid of that size.
+func WithEngine(newEngine func(width, height int) engine.Engine) Option {
+	return func(g *Game) {
+		g.newEngine = newEngine
+	}
+}
+
+// WithPattern sets the pattern placed in the center of the grid when the game
+// is created, or when the terminal size is known if the grid fits the
+// terminal. The grid grows to fit the pattern.
+func WithPattern(p *pattern.Pattern) Option {
+	return func(g *Game) {
+		g.pattern = p
+	}
+}
+
+// WithMacrocell sets the Macrocell pattern loaded when the game is created,
+// or when the terminal size is known if the grid fits the terminal. The
+// pattern that fits into the grid is placed in its center like the other
+// patterns. Otherwise, the cells keep their coordinates in the quadtree
+// engine, so the pattern is centered around the origin.
+func WithMacrocell(m *pattern.Macrocell) Option {
+	return func(g *Game) {
+		g.macrocell = m
+	}
+}
+
+// WithQuadtreeEngine sets the function that creates the engine the cells are
+// moved to when the Macrocell pattern does not fit into the grid. Without it,
+// the grid grows to fit the pattern.
+func WithQuadtreeEngine(newEngine func() engine.Engine) Option {
+	return func(g *Game) {
+		g.newQuadtree = newEngine
+	}
+}
+
+// WithTickInterval sets the time between the generations when the game is
+// started. By default, it is 500ms. The speed can be changed while the game
+// is running, and the interval is replaced with one of the predefined ones.
+func WithTickInterval(d time.Duration) Option {
+	return func(g *Game) {
+		g.interval = d
+	}
+}
+
+// WithRandomCells fills the visible area with random alive cells when the
+// game is created. The density is the probability of a cell to be alive, and
+// the same seed always gives the same cells. If the density is zero, the
+// cells are not added, but the seed and the density are used by the soups
+// filled later.
+func WithRandomCells(seed uint64, density float64) Option {
+	return func(g *Game) {
+		g.seed = seed
+		g.density = density
+	}
+}
+
+// WithSoup sets the region and the symmetry of the random soups, including
+// the one filled when the game is created. If the size is positive, the
+// soups are filled into the centered square of that size, otherwise into the
+// whole visible area. A selection takes precedence over both.
+func WithSoup(size int, symmetry soup.Symmetry) Option {
+	return func(g *Game) {
+		g.soupSize = size
+		g.symmetry = symmetry
+	}
+}
+
+// WithSoupRegion sets the cells the random soups are filled into, including
+// the one filled when the game is created. It takes precedence over the size
+// of the soups, and a selection takes precedence over it.
+func WithSoupRegion(r engine.Rect) Option {
+	return func(g *Game) {
+		g.soupArea = r
+	}
+}
+
+// WithFitTerminal resizes the game to fit the terminal every time the size of
+// the terminal changes. The bounded engines are resized as well. Otherwise,
+// the size of the game is fixed, and only the visible area is limited by the
+// terminal.
+func WithFitTerminal() Option {
+	return func(g *Game) {
+		g.fitTerminal = true
+	}
+}
+
+// WithRenderer sets the renderer that draws the cells. It can be switched
+// while the game is running. By default, a cell is drawn with a square.
+func WithRenderer(r render.Renderer) Option {
+	return func(g *Game) {
+		g.renderer = r
+	}
+}
+
+// WithAutoPause pauses the game when the cells become static or periodic. It
+// can be switched while the game is running.
+func WithAutoPause() Option {
+	return func(g *Game) {
+		g.autoPause = true
+	}
+}
+
+// WithSavePath sets the file the current state of the cells is saved to.
+func WithSavePath(path string) Option {
+	return func(g *Game) {
+		g.savePath = path
+	}
+}
+
+// WithCensus sets the largest distance between two cells of the same object
+// in the census, and the file the census is saved to.
+func WithCensus(distance int, path string) Option {
+	return func(g *Game) {
+		g.censusDistance = distance
+		g.censusPath = path
+	}
+}
+
+// WithSystemClipboard sets where the copied cells are written to as the
+// OSC 52 escape sequence that puts them into the system clipboard. By
+// default, it is the standard error, so it reaches the terminal without
+// interfering with the rendering.
+func WithSystemClipboard(w io.Writer) Option {
+	return func(g *Game) {
+		g.systemClipboard = w
+	}
+}
+
+// New creates a new game with the specified width and height of the visible area.
+func New(width, height int, opts ...Option) *Game {
+	g := &Game{
+		width:           width,
+		height:          height,
+		interval:        defaultTickInterval,
+		skip:            defaultSkip,
+		viewport:        viewport.New(width, height),
+		renderer:        render.Blocks{},
+		history:         history.New(historyLimit),
+		timeline:        history.NewTimeline(keyframeInterval, keyframeLimit),
+		detector:        period.NewDetector(maxPeriod),
+		spinner:         newSpinner(),
+		help:            help.New(),
+		keys:            gameKeys,
+		systemClipboard: os.Stderr,
+		censusDistance:  census.DefaultDistance,
+		newEngine: func(width, height int) engine.Engine {
+			return grid.New(width, height)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.universe = g.newEngine(g.width, g.height)
+
+	// The size of the grid that fits the terminal is known only after the
+	// first resize, so the cells are placed then.
+	if !g.fitTerminal {
+		g.place()
+	}
+
+	return g
+}
+
+// place places the pattern and fills the soup set when the game is created,
+// and records the first state of the cells.
+func (g *Game) place() {
+	g.placed = true
+
+	if g.pattern != nil {
+		g.placePattern(g.pattern)
+	}
+
+	if g.macrocell != nil {
+		g.placeMacrocell(g.macrocell)
+	}
+
+	if g.density > 0 {
+		g.addSoup()
+	}
+
+	g.record()
+}
+
+// placeMacrocell places the Macrocell pattern into the grid if it fits, so
+// the decision follows the size of the grid fitted to the terminal. The larger
+// patterns are loaded into the quadtree engine as they are, and the engine
+// stays for the rest of the game.
+func (g *Game) placeMacrocell(m *pattern.Macrocell) {
+	_, isQuadtree := g.universe.(engine.Quadtree)
+
+	board, bounded := g.board()
+	if !bounded {
+		board = g.viewport.Bounds()
+	}
+
+	bounds := m.BoundingBox()
+	fits := bounds.Width() <= board.Width() && bounds.Height() <= board.Height()
+
+	switch {
+	case isQuadtree:
+	case fits || g.newQuadtree == nil:
+		g.placePattern(m.Pattern())
+		return
+	default:
+		newQuadtree := g.newQuadtree
+		g.newEngine = func(int, int) engine.Engine { return newQuadtree() }
+		g.universe = newQuadtree()
+	}
+
+	m.Place(g.universe)
+	g.viewport.Fit(g.universe.BoundingBox())
 }
 
-// New creates a new game with the specified width and height for the grid.
-func New(width, height int) *Game {
-	return &Game{
-		width:   width,
-		height:  height,
-		grid:    grid.New(width, height),
-		spinner: newSpinner(),
-		keys:    gameKeys,
+// placePattern places the pattern in the center of the bounded engine. The
+// engine grows to fit the pattern if it can, otherwise the cells outside it
+// are dropped and the status tells about it.
+func (g *Game) placePattern(p *pattern.Pattern) {
+	board, bounded := g.board()
+	if bounded && (p.Width > board.Width() || p.Height > board.Height()) {
+		resizable, ok := g.universe.(engine.Resizable)
+		if !ok {
+			g.status = fmt.Sprintf(
+				"The %dx%d pattern does not fit into the %dx%d grid, and the cells outside it are dropped.",
+				p.Width, p.Height, board.Width(), board.Height(),
+			)
+		} else {
+			g.width = max(g.width, p.Width)
+			g.height = max(g.height, p.Height)
+			resizable.Resize(g.width, g.height)
+			g.viewport.CenterOn(engine.Point{X: g.width / 2, Y: g.height / 2})
+		}
 	}
+
+	p.Place(g.universe, (g.width-p.Width)/2, (g.height-p.Height)/2)
 }
 
 // Init initializes the game.
@@ -49,15 +350,40 @@ func (g *Game) Init() tea.Cmd {
 // Update updates the game state depending on the message received.
 func (g *Game) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := message.(type) {
+	case tea.WindowSizeMsg:
+		return g.handleWindowSize(msg)
 	case tea.KeyMsg:
+		if g.browser != nil {
+			return g.handleBrowserKey(msg)
+		}
+
+		if g.censusTable != nil {
+			return g.handleCensusKey(msg)
+		}
+
 		return g.handlePressedKey(msg)
 	case tea.MouseMsg:
+		// The cells are hidden by the library browser, the census and the
+		// help.
+		if g.browser != nil || g.censusTable != nil || g.showHelp {
+			return g, nil
+		}
+
 		return g.handleMouseEvent(msg)
 	case spinner.TickMsg:
 		return g.handleSpinnerTick(msg)
 	case tickMsg:
-		return g.handleTick()
+		return g.handleTick(msg)
 	}
+
+	// The results of the search and the other messages of the list.
+	if g.browser != nil {
+		var cmd tea.Cmd
+		*g.browser, cmd = g.browser.Update(message)
+
+		return g, cmd
+	}
+
 	return g, nil
 }
 
@@ -65,36 +391,109 @@ func (g *Game) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 func (g *Game) View() string {
 	var sb strings.Builder
 
-	sb.WriteString("============================ Conway's Game of Life ============================\n")
-	sb.WriteString("Use the mouse cursor and the left button to set the cell state.                \n")
-	sb.WriteString("Press 'r' to reset the game, '␣' to start/pause the game, 'q' to quit the game.\n")
-	sb.WriteString("===============================================================================\n")
-
-	// Render the grid.
-	currentState := g.grid.State()
-	for y := 0; y < len(currentState); y++ {
-		for x := 0; x < len(currentState[y]); x++ {
-			currentCell := currentState[y][x]
-			if currentCell == nil {
-				sb.WriteString("□ ")
-				continue
-			}
-
-			sb.WriteString(setGreenColor)
-			sb.WriteString("■ ")
-			sb.WriteString(resetColor)
-		}
+	for _, line := range header {
+		sb.WriteString(truncate(line, g.terminalWidth))
+		sb.WriteString("\n")
+	}
 
+	// Render the visible part of the universe, or the library browser or the
+	// census in its place.
+	switch {
+	case g.browser != nil:
+		g.renderBrowser(&sb)
+	case g.censusTable != nil:
+		g.renderCensus(&sb)
+	case g.showHelp:
+		g.renderHelp(&sb)
+	case g.showStats:
+		// The statistics are shown next to the cells and the timeline.
+		var cells strings.Builder
+		g.renderCells(&cells)
+		g.renderTimeline(&cells)
+
+		sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, strings.TrimSuffix(cells.String(), "\n"), g.renderStats()))
 		sb.WriteString("\n")
+	default:
+		g.renderCells(&sb)
+		g.renderTimeline(&sb)
 	}
 
-	// Render the generation number.
-	generation := fmt.Sprintf("%s Generation: %d\n", g.spinner.View(), g.grid.Generation())
+	// Render the generation number, the speed, the renderer, the cursor, the
+	// selection, the seed and the period.
+	generation := fmt.Sprintf(
+		"%s Generation: %d | Interval: %s | Skip: %d | View: %s",
+		g.spinner.View(), g.universe.Generation(), g.interval, g.skip, g.renderer.Name(),
+	)
 	sb.WriteString(generation)
 
+	if g.cursor != nil {
+		sb.WriteString(g.cursorStatus())
+	}
+
+	sb.WriteString(g.selectionStatus())
+	sb.WriteString(g.soupStatus())
+	sb.WriteString(g.periodStatus())
+
+	sb.WriteString("\n")
+
+	if g.status != "" {
+		sb.WriteString(g.status)
+		sb.WriteString("\n")
+	}
+
 	return sb.String()
 }
 
+func (g *Game) handleWindowSize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	g.terminalWidth = msg.Width
+	g.terminalHeight = msg.Height
+	g.help.Width = msg.Width
+
+	// The cells on a resized torus evolve differently.
+	if g.fitTerminal {
+		g.resize()
+		g.detector.Reset()
+	}
+
+	g.viewport.Resize(g.terminalCapacity())
+	g.resizeBrowser()
+	g.resizeCensus()
+
+	if !g.placed {
+		g.place()
+	}
+
+	return g, nil
+}
+
+// switchRenderer switches to the next renderer. The number of screen cells
+// that fit into the terminal changes, so the viewport is resized keeping the
+// cell in its center.
+func (g *Game) switchRenderer() {
+	renderers := render.All()
+
+	next := 0
+	for i, r := range renderers {
+		if r.Name() == g.renderer.Name() {
+			next = (i + 1) % len(renderers)
+		}
+	}
+
+	g.renderer = renderers[next]
+
+	if g.terminalWidth == 0 || g.terminalHeight == 0 {
+		return
+	}
+
+	if g.fitTerminal {
+		g.resize()
+	}
+
+	center := g.viewport.Center()
+	g.viewport.Resize(g.terminalCapacity())
+	g.viewport.CenterOn(center)
+}
+
 func (g *Game) handleSpinnerTick(msg spinner.TickMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	g.spinner, cmd = g.spinner.Update(msg)
@@ -102,6 +501,18 @@ func (g *Game) handleSpinnerTick(msg spinner.TickMsg) (tea.Model, tea.Cmd) {
 }
 
 func (g *Game) handlePressedKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The text pasted into the terminal is read as a pattern.
+	if msg.Paste {
+		g.pasteText(string(msg.Runes))
+		return g, nil
+	}
+
+	// The movement keys move the cursor instead of the viewport when the
+	// cursor is shown.
+	if g.cursor != nil && g.handleCursorKey(msg) {
+		return g, nil
+	}
+
 	switch {
 	case key.Matches(msg, g.keys.Quit):
 		// Quit the game.
@@ -109,87 +520,319 @@ func (g *Game) handlePressedKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, g.keys.Reset):
 		// Reset the game.
 		g.started = false
-		g.resetGrid()
+		g.resetUniverse()
 		g.resetSpinner()
 
+		return g, nil
+	case key.Matches(msg, g.keys.Save):
+		// Save the current state of the cells.
+		g.save()
+
+		return g, nil
+	case key.Matches(msg, g.keys.Up):
+		g.viewport.Pan(0, -1)
+		return g, nil
+	case key.Matches(msg, g.keys.Down):
+		g.viewport.Pan(0, 1)
+		return g, nil
+	case key.Matches(msg, g.keys.Left):
+		g.viewport.Pan(-1, 0)
+		return g, nil
+	case key.Matches(msg, g.keys.Right):
+		g.viewport.Pan(1, 0)
+		return g, nil
+	case key.Matches(msg, g.keys.ZoomIn):
+		g.viewport.ZoomIn()
+		return g, nil
+	case key.Matches(msg, g.keys.ZoomOut):
+		g.viewport.ZoomOut()
+		return g, nil
+	case key.Matches(msg, g.keys.FitPattern):
+		g.viewport.Fit(g.universe.BoundingBox())
+		return g, nil
+	case key.Matches(msg, g.keys.CenterOnOrigin):
+		g.viewport.CenterOn(engine.Point{})
+		return g, nil
+	case key.Matches(msg, g.keys.ToggleCursor):
+		g.toggleCursor()
+		return g, nil
+	case key.Matches(msg, g.keys.SwitchRenderer):
+		g.switchRenderer()
+		return g, nil
+	case key.Matches(msg, g.keys.Stats):
+		g.toggleStats()
+		return g, nil
+	case key.Matches(msg, g.keys.Help):
+		g.showHelp = !g.showHelp
+		return g, nil
+	case key.Matches(msg, g.keys.Census):
+		g.openCensus()
+		return g, nil
+	case key.Matches(msg, g.keys.AutoPause):
+		g.toggleAutoPause()
+		return g, nil
+	case key.Matches(msg, g.keys.Soup):
+		g.fillSoup()
+		return g, nil
+	case key.Matches(msg, g.keys.Symmetry):
+		g.cycleSymmetry()
+		return g, nil
+	case key.Matches(msg, g.keys.Library):
+		g.openLibrary()
+		return g, nil
+	case key.Matches(msg, g.keys.Select):
+		g.toggleSelecting()
+		return g, nil
+	case key.Matches(msg, g.keys.Copy):
+		g.copySelection()
+		return g, nil
+	case key.Matches(msg, g.keys.Cut):
+		g.cutSelection()
+		return g, nil
+	case key.Matches(msg, g.keys.Clear):
+		g.clearSelection()
+		return g, nil
+	case key.Matches(msg, g.keys.Fill):
+		g.fillSelection()
+		return g, nil
+	case key.Matches(msg, g.keys.Paste):
+		g.togglePasting()
+		return g, nil
+	case key.Matches(msg, g.keys.Rotate):
+		g.transformClipboard((*pattern.Pattern).Rotate)
+		return g, nil
+	case key.Matches(msg, g.keys.FlipHorizontal):
+		g.transformClipboard((*pattern.Pattern).FlipHorizontal)
+		return g, nil
+	case key.Matches(msg, g.keys.FlipVertical):
+		g.transformClipboard((*pattern.Pattern).FlipVertical)
+		return g, nil
+	case key.Matches(msg, g.keys.Step):
+		// Only a paused game can be moved one generation forward.
+		if !g.started {
+			g.step()
+		}
+
+		return g, nil
+	case key.Matches(msg, g.keys.Skip):
+		g.skipGenerations()
+		return g, nil
+	case key.Matches(msg, g.keys.Faster):
+		g.interval = faster(g.interval)
+		return g, nil
+	case key.Matches(msg, g.keys.Slower):
+		g.interval = slower(g.interval)
+		return g, nil
+	case key.Matches(msg, g.keys.IncreaseSkip):
+		g.increaseSkip()
+		return g, nil
+	case key.Matches(msg, g.keys.DecreaseSkip):
+		g.decreaseSkip()
+		return g, nil
+	case key.Matches(msg, g.keys.Undo):
+		g.undo()
+		return g, nil
+	case key.Matches(msg, g.keys.Redo):
+		g.redo()
+		return g, nil
+	case key.Matches(msg, g.keys.Rewind):
+		g.rewind()
+		return g, nil
+	case key.Matches(msg, g.keys.Back):
+		g.back()
+		return g, nil
+	case key.Matches(msg, g.keys.SeekBackward):
+		g.seek(g.universe.Generation() - g.skip)
+		return g, nil
+	case key.Matches(msg, g.keys.SeekForward):
+		g.seek(g.universe.Generation() + g.skip)
+		return g, nil
+	case key.Matches(msg, g.keys.SeekFirst):
+		g.seek(g.timeline.First())
+		return g, nil
+	case key.Matches(msg, g.keys.SeekLast):
+		g.seek(g.timeline.Last())
 		return g, nil
 	case key.Matches(msg, g.keys.ToggleStartPause):
 		// Start or pause the game.
-		g.started = !g.started
 		if g.started {
-			return g, tea.Batch(g.tick(), g.spinner.Tick)
+			g.pause()
+			return g, nil
 		}
 
-		g.resetSpinner()
+		// The game can be rewound to the state it is started from.
+		g.started = true
+		g.tickID++
+		g.history.MarkStart()
 
-		return g, nil
+		return g, tea.Batch(g.tick(), g.spinner.Tick)
 	default:
 		// Ignore other keys.
-		if g.started {
-			return g, g.tick()
-		}
-
 		return g, nil
 	}
 }
 
 func (g *Game) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	// We can set the cells only if the game is not started yet or during the
-	// pause.
-	if g.started {
+	// The viewport can be zoomed and panned even when the game is started.
+	if g.handleViewportMouseEvent(msg) {
 		return g, nil
 	}
 
-	// We can set the cell state only by pressing the left mouse button.
-	// All other buttons and actions will be ignored.
-	if !mouse.IsLeftButtonPressed(msg) {
+	l := g.layout()
+
+	// The timeline is rendered below the characters, and a click on it
+	// seeks to the generation under the mouse cursor.
+	if mouse.IsLeftButtonPressed(msg) && msg.Y == l.top+l.lines() {
+		if generation, ok := g.timelineGeneration(msg.X); ok {
+			g.seek(generation)
+		}
+
 		return g, nil
 	}
 
-	// We have a spaces between cells in rows, so we need to multiply the width
-	// by 2.
-	gridXMin := 0
-	gridXMax := g.width * 2
+	// The painting is finished even if the game is started meanwhile.
+	if mouse.IsReleased(msg) {
+		g.stopPainting()
+		return g, nil
+	}
 
-	// The first three lines is the title and help, we need to shift the grid by one line down.
-	gridYMin := 4
-	gridYMax := g.height + gridYMin
+	// The cells can be selected and the ghost follows the mouse even when
+	// the game is started.
+	if g.handleSelectionMouseEvent(msg) {
+		return g, nil
+	}
 
-	// We can handle only mouse clicks withing the cell grid in terminal.
-	if !mouse.IsClickWithinArea(msg, gridXMin, gridYMin, gridXMax, gridYMax) {
+	// We can set the cells only if the game is not started yet or during the
+	// pause.
+	if g.started {
 		return g, nil
 	}
-	// Each cell is represented by width 2 (one for the cell and one for
-	// the space). We need to handle only the cell click.
-	if msg.X%2 == 0 {
-		g.grid.ToggleCell(msg.X/2, msg.Y-gridYMin)
+
+	switch {
+	case mouse.IsLeftButtonPressed(msg) || mouse.IsRightButtonPressed(msg):
+		// The screen cells are rendered below the header with the characters
+		// of the renderer. We need to handle only the clicks on the
+		// characters, and translate them through the viewport into the cells.
+		x, y, ok := l.screenCellAt(msg.X, msg.Y)
+		if !ok {
+			return g, nil
+		}
+
+		g.startPainting(g.viewport.Cell(x, y), mouse.IsRightButtonPressed(msg))
+	case g.paint != nil && mouse.IsDragged(msg):
+		// The spaces after the characters are painted over as well, but the
+		// painting stops at the edges of the viewport.
+		if p, ok := g.cellUnderMouse(msg); ok {
+			g.continuePainting(p)
+		}
 	}
 
 	return g, nil
 }
 
-func (g *Game) handleTick() (tea.Model, tea.Cmd) {
-	if !g.started {
+// cellUnderMouse returns the cell drawn by the character under the mouse
+// cursor, including the space after the character. It returns false if the
+// mouse cursor is outside the viewport.
+func (g *Game) cellUnderMouse(msg tea.MouseMsg) (engine.Point, bool) {
+	l := g.layout()
+
+	x, y := l.screenCellAtChar(msg.X, msg.Y)
+	if x < 0 || y < 0 || x >= l.columns || y >= l.rows {
+		return engine.Point{}, false
+	}
+
+	return g.viewport.Cell(x, y), true
+}
+
+// handleViewportMouseEvent zooms the viewport with the mouse wheel and pans
+// it by dragging with the middle button. It returns false if the event is
+// not about the viewport.
+func (g *Game) handleViewportMouseEvent(msg tea.MouseMsg) bool {
+	// The screen cells are counted from the top left one, and the spaces
+	// after the characters are counted as well.
+	x, y := g.layout().screenCellAtChar(msg.X, msg.Y)
+
+	switch {
+	case mouse.IsWheelUp(msg):
+		g.viewport.ZoomIn()
+	case mouse.IsWheelDown(msg):
+		g.viewport.ZoomOut()
+	case mouse.IsMiddleButtonPressed(msg):
+		g.drag = &drag{x: x, y: y, viewport: g.viewport}
+	case g.drag != nil && mouse.IsMotion(msg):
+		g.viewport = g.drag.viewport
+		g.viewport.Scroll(g.drag.x-x, g.drag.y-y)
+	case g.drag != nil && mouse.IsReleased(msg):
+		g.drag = nil
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (g *Game) handleTick(msg tickMsg) (tea.Model, tea.Cmd) {
+	if !g.started || msg.id != g.tickID {
 		return g, nil
 	}
 
-	g.grid.NextGeneration()
+	g.step()
+
+	// The game is paused once the cells become stable with the auto-pause.
+	if !g.started {
+		return g, nil
+	}
 
 	return g, g.tick()
 }
 
 func (g *Game) tick() tea.Cmd {
-	return tea.Tick(speed, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+	id := g.tickID
+
+	return tea.Tick(g.interval, func(time.Time) tea.Msg {
+		return tickMsg{id: id}
 	})
 }
 
+func (g *Game) save() {
+	if g.savePath == "" {
+		g.status = "The file to save the cells is not set."
+		return
+	}
+
+	// The Macrocell format keeps the quadtree of the engine as it is.
+	var err error
+	if format, _ := pattern.FormatFromExtension(g.savePath); format == pattern.MC {
+		err = pattern.SaveMacrocell(g.savePath, pattern.MacrocellFromEngine(g.universe))
+	} else {
+		err = pattern.Save(g.savePath, pattern.FromEngine(g.universe))
+	}
+
+	if err != nil {
+		g.status = fmt.Sprintf("Failed to save the cells: %v", err)
+		return
+	}
+
+	g.status = fmt.Sprintf("Saved the cells to %s.", g.savePath)
+}
+
+// pause stops the generations.
+func (g *Game) pause() {
+	g.started = false
+	g.resetSpinner()
+}
+
 func (g *Game) resetSpinner() {
 	g.spinner = newSpinner()
 }
 
-func (g *Game) resetGrid() {
-	g.grid = grid.New(g.width, g.height)
+func (g *Game) resetUniverse() {
+	g.universe = g.newEngine(g.width, g.height)
+	g.history.Clear()
+	g.timeline.Clear()
+	g.detector.Reset()
+	g.populations = nil
+	g.record()
 }
 
 func newSpinner() spinner.Model {
@@ -0,0 +1,994 @@
+package game_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game"
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/hashlife"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+const (
+	// headerHeight is the number of lines above the grid.
+	headerHeight = 3
+	// footerHeight is the number of lines below the grid.
+	footerHeight = 3
+)
+
+func TestGame_WindowSize(t *testing.T) {
+	sg := grid.New(10, 5)
+	sg.SetCell(9, 4, cell.Alive)
+
+	g := game.New(10, 5, game.WithFitTerminal(), game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+
+	// The terminal fits 30 columns and 10 rows of cells.
+	g.Update(tea.WindowSizeMsg{Width: 60, Height: headerHeight + 10 + footerHeight})
+	assert.Equal(t, 30, sg.Width())
+	assert.Equal(t, 10, sg.Height())
+	assert.Equal(t, cell.Alive, sg.Cell(9, 4))
+	assert.Len(t, gridLines(g), 10)
+
+	// The grid never becomes smaller than the alive cells, but only the
+	// cells that fit into the terminal are visible.
+	g.Update(tea.WindowSizeMsg{Width: 8, Height: headerHeight + 3 + footerHeight})
+	assert.Equal(t, 10, sg.Width())
+	assert.Equal(t, 5, sg.Height())
+	assert.Equal(t, cell.Alive, sg.Cell(9, 4))
+
+	lines := gridLines(g)
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "□ □ □ □ ", lines[0])
+}
+
+func TestGame_WindowSizeWithFixedSize(t *testing.T) {
+	sg := grid.New(10, 5)
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+
+	g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 8 + footerHeight})
+	assert.Equal(t, 10, sg.Width())
+	assert.Equal(t, 5, sg.Height())
+
+	// The screen cells outside the grid are blank.
+	lines := gridLines(g)
+	assert.Len(t, lines, 8)
+	assert.Equal(t, strings.Repeat("□ ", 10)+strings.Repeat("  ", 10), lines[0])
+	assert.Equal(t, strings.Repeat(" ", 40), lines[5])
+}
+
+func TestGame_WindowSizeWithUnboundedEngine(t *testing.T) {
+	u := sparse.New()
+
+	g := game.New(10, 5, game.WithFitTerminal(), game.WithEngine(func(width, height int) engine.Engine {
+		return u
+	}))
+
+	g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 7 + footerHeight})
+
+	lines := gridLines(g)
+	assert.Len(t, lines, 7)
+	assert.Equal(t, strings.Repeat("□ ", 20), lines[0])
+}
+
+func TestGame_Pattern(t *testing.T) {
+	// The pattern is wider than the grid before the terminal size is known.
+	p := &pattern.Pattern{Width: 30, Height: 2, Cells: []engine.Point{{X: 0, Y: 0}, {X: 29, Y: 1}}}
+
+	var sg *grid.Grid
+	newGrid := game.WithEngine(func(width, height int) engine.Engine {
+		sg = grid.New(width, height)
+		return sg
+	})
+
+	t.Run("the grid fits the terminal", func(t *testing.T) {
+		g := game.New(10, 5, game.WithFitTerminal(), game.WithPattern(p), newGrid)
+		assert.Empty(t, engine.AliveCells(sg))
+
+		// The pattern is centered in the grid fitted to the terminal.
+		g.Update(tea.WindowSizeMsg{Width: 80, Height: headerHeight + 10 + footerHeight})
+		assert.Equal(t, 40, sg.Width())
+		assert.Equal(t, 10, sg.Height())
+		assert.Equal(t, []engine.Point{{X: 5, Y: 4}, {X: 34, Y: 5}}, engine.AliveCells(sg))
+	})
+
+	t.Run("the grid grows to fit the pattern", func(t *testing.T) {
+		game.New(10, 5, game.WithPattern(p), newGrid)
+		assert.Equal(t, 30, sg.Width())
+		assert.Equal(t, 5, sg.Height())
+		assert.Equal(t, []engine.Point{{X: 0, Y: 1}, {X: 29, Y: 2}}, engine.AliveCells(sg))
+	})
+}
+
+func TestGame_Macrocell(t *testing.T) {
+	// The pattern is wider than the grid before the terminal size is known.
+	m := pattern.MacrocellFromPattern(&pattern.Pattern{Width: 30, Height: 2, Cells: []engine.Point{{X: 0, Y: 0}, {X: 29, Y: 1}}})
+
+	var (
+		sg *grid.Grid
+		hl *hashlife.Universe
+	)
+
+	newGame := func() *game.Game {
+		sg, hl = nil, nil
+
+		return game.New(10, 5, game.WithFitTerminal(), game.WithMacrocell(m),
+			game.WithEngine(func(width, height int) engine.Engine {
+				sg = grid.New(width, height)
+				return sg
+			}),
+			game.WithQuadtreeEngine(func() engine.Engine {
+				hl = hashlife.New()
+				return hl
+			}),
+		)
+	}
+
+	t.Run("the pattern fits the terminal", func(t *testing.T) {
+		g := newGame()
+		g.Update(tea.WindowSizeMsg{Width: 80, Height: headerHeight + 10 + footerHeight})
+		assert.Nil(t, hl)
+		assert.Equal(t, []engine.Point{{X: 5, Y: 4}, {X: 34, Y: 5}}, engine.AliveCells(sg))
+	})
+
+	t.Run("the pattern does not fit the terminal", func(t *testing.T) {
+		g := newGame()
+		g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 10 + footerHeight})
+		require.NotNil(t, hl)
+		assert.Empty(t, engine.AliveCells(sg))
+		assert.Len(t, engine.AliveCells(hl), 2)
+
+		// The quadtree engine stays when the game is reset.
+		previous := hl
+		g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		assert.NotSame(t, previous, hl)
+	})
+}
+
+func TestGame_MouseClick(t *testing.T) {
+	tt := []struct {
+		name     string
+		x        int
+		y        int
+		expected []engine.Point
+	}{
+		{
+			name:     "click on the first cell",
+			x:        0,
+			y:        headerHeight,
+			expected: []engine.Point{{X: 0, Y: 0}},
+		},
+		{
+			name:     "click on the last visible cell",
+			x:        2 * 3,
+			y:        headerHeight + 2,
+			expected: []engine.Point{{X: 3, Y: 2}},
+		},
+		{
+			name: "click on the space between cells",
+			x:    1,
+			y:    headerHeight,
+		},
+		{
+			name: "click on the header",
+			x:    0,
+			y:    headerHeight - 1,
+		},
+		{
+			name: "click to the right of the visible cells",
+			x:    2 * 4,
+			y:    headerHeight,
+		},
+		{
+			name: "click below the visible cells",
+			x:    0,
+			y:    headerHeight + 3,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := grid.New(10, 5)
+
+			g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+				return sg
+			}))
+
+			// The terminal fits 4 columns and 3 rows of cells.
+			g.Update(tea.WindowSizeMsg{Width: 8, Height: headerHeight + 3 + footerHeight})
+			g.Update(tea.MouseMsg{X: tc.x, Y: tc.y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+
+			assert.Equal(t, tc.expected, engine.AliveCells(sg))
+		})
+	}
+}
+
+func TestGame_PanAndClick(t *testing.T) {
+	u := sparse.New()
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return u
+	}))
+
+	// Pan two cells to the left and one cell up.
+	for _, k := range []tea.KeyMsg{
+		{Type: tea.KeyLeft},
+		{Type: tea.KeyRunes, Runes: []rune("h")},
+		{Type: tea.KeyUp},
+	} {
+		g.Update(k)
+	}
+
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, []engine.Point{{X: -2, Y: -1}}, engine.AliveCells(u))
+
+	// Drag the viewport back with the middle button.
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Button: tea.MouseButtonMiddle, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 2 * 2, Y: headerHeight + 1, Button: tea.MouseButtonMiddle, Action: tea.MouseActionMotion})
+	g.Update(tea.MouseMsg{X: 2 * 2, Y: headerHeight + 1, Button: tea.MouseButtonNone, Action: tea.MouseActionRelease})
+
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, []engine.Point{{X: -4, Y: -2}, {X: -2, Y: -1}}, engine.AliveCells(u))
+}
+
+func TestGame_ZoomAndFit(t *testing.T) {
+	u := sparse.New()
+	u.SetCell(0, 0, cell.Alive)
+	u.SetCell(7, 0, cell.Alive)
+
+	g := game.New(4, 2, game.WithEngine(func(width, height int) engine.Engine {
+		return u
+	}))
+
+	// A screen cell shows 2x2 cells.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	assert.Equal(t, []string{"□ □ □ □ ", "□ □ ■ □ "}, gridLines(g))
+
+	// Both cells fit into the viewport when a screen cell shows 2x2 cells.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	assert.Equal(t, []string{"■ □ □ ■ ", "□ □ □ □ "}, gridLines(g))
+
+	// The mouse wheel zooms in and out keeping the center of the viewport.
+	g.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	assert.Equal(t, []string{"□ □ □ □ ", "□ □ □ □ "}, gridLines(g))
+
+	g.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	assert.Equal(t, []string{"■ □ □ ■ ", "□ □ □ □ "}, gridLines(g))
+}
+
+func TestGame_SwitchRenderer(t *testing.T) {
+	u := sparse.New()
+
+	g := game.New(5, 2, game.WithEngine(func(width, height int) engine.Engine {
+		return u
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 10, Height: headerHeight + 2 + footerHeight})
+
+	// The half blocks draw 10x4 screen cells centered on the same cell, and
+	// a character draws two screen cells, one above another.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	g.Update(tea.MouseMsg{X: 3, Y: headerHeight + 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, []engine.Point{{X: 0, Y: 1}}, engine.AliveCells(u))
+	assert.Equal(t, []string{"          ", "   ▀      "}, gridLines(g))
+
+	// The braille patterns draw 20x8 screen cells, and a character draws 2x4
+	// screen cells.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	g.Update(tea.MouseMsg{X: 5, Y: headerHeight + 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, []engine.Point{{X: 0, Y: 1}, {X: 2, Y: 1}}, engine.AliveCells(u))
+	assert.Contains(t, g.View(), "View: braille")
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	assert.Contains(t, g.View(), "View: blocks")
+}
+
+func TestGame_StepAndSkip(t *testing.T) {
+	sg := grid.New(5, 5)
+	for x := 1; x <= 3; x++ {
+		sg.SetCell(x, 2, cell.Alive)
+	}
+
+	g := game.New(5, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, 1, sg.Generation())
+	assert.Contains(t, g.View(), "Generation: 1 | Interval: 500ms | Skip: 100 |")
+
+	// The number of generations to skip is changed by the factor of ten.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("}")})
+	assert.Contains(t, g.View(), "Skip: 1000 |")
+
+	// The grid calculates every skipped generation, so the skip is limited.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("}")})
+	assert.Contains(t, g.View(), "Skip: 1000 |")
+
+	for range 4 {
+		g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("{")})
+	}
+	assert.Contains(t, g.View(), "Skip: 1 |")
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("}")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	assert.Equal(t, 11, sg.Generation())
+	assert.Equal(t, []engine.Point{{X: 2, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 3}}, engine.AliveCells(sg))
+	assert.Contains(t, g.View(), "Skipped 10 generations")
+
+	// The started game is not stepped.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, 11, sg.Generation())
+}
+
+func TestGame_Speed(t *testing.T) {
+	tt := []struct {
+		name     string
+		interval time.Duration
+		keys     string
+		expected string
+	}{
+		{name: "faster", interval: 500 * time.Millisecond, keys: "]", expected: "250ms"},
+		{name: "slower", interval: 500 * time.Millisecond, keys: "[[", expected: "2s"},
+		{name: "fastest", interval: 20 * time.Millisecond, keys: "]]]", expected: "10ms"},
+		{name: "slowest", interval: time.Second, keys: "[[", expected: "2s"},
+		{name: "faster than an interval between speeds", interval: 300 * time.Millisecond, keys: "]", expected: "250ms"},
+		{name: "slower than an interval between speeds", interval: 300 * time.Millisecond, keys: "[", expected: "500ms"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			g := game.New(5, 5, game.WithTickInterval(tc.interval))
+
+			for _, r := range tc.keys {
+				g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			}
+
+			assert.Contains(t, g.View(), "Interval: "+tc.expected+" |")
+		})
+	}
+}
+
+func TestGame_UndoAndRewind(t *testing.T) {
+	u := sparse.New()
+
+	g := game.New(4, 3, game.WithEngine(func(width, height int) engine.Engine {
+		return u
+	}))
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+
+	// Draw a blinker.
+	for x := 0; x < 3; x++ {
+		click(g, 2*x, headerHeight+1)
+	}
+	blinker := engine.AliveCells(u)
+	assert.Len(t, blinker, 3)
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.Len(t, engine.AliveCells(u), 2)
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("U")})
+	assert.Equal(t, blinker, engine.AliveCells(u))
+
+	// Run the game for a few generations and rewind it to the start.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+	for range 3 {
+		g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	}
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	assert.Equal(t, 2, u.Generation())
+	assert.Equal(t, blinker, engine.AliveCells(u))
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	assert.Equal(t, 0, u.Generation())
+	assert.Equal(t, blinker, engine.AliveCells(u))
+	assert.Contains(t, g.View(), "Generation: 0 |")
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	assert.Contains(t, g.View(), "Nothing to rewind to.")
+
+	// Resetting the game clears the history.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.Contains(t, g.View(), "Nothing to undo.")
+}
+
+func TestGame_Timeline(t *testing.T) {
+	sg := grid.New(10, 5)
+	for x := 1; x <= 3; x++ {
+		sg.SetCell(x, 2, cell.Alive)
+	}
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 20, Height: headerHeight + 5 + footerHeight})
+
+	for range 10 {
+		g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	}
+
+	// The bar takes the width of the board together with the range.
+	assert.Equal(t, strings.Repeat("─", 14)+"● 0-10", timelineLine(g))
+
+	// A click on the bar seeks to the generation under the mouse cursor.
+	g.Update(tea.MouseMsg{X: 7, Y: headerHeight + 5, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 5, sg.Generation())
+	assert.Equal(t, strings.Repeat("─", 7)+"●"+strings.Repeat("─", 7)+" 0-10", timelineLine(g))
+	assert.Equal(t, []engine.Point{{X: 2, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 3}}, engine.AliveCells(sg))
+
+	tt := []struct {
+		key      tea.KeyMsg
+		expected int
+	}{
+		{key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(".")}, expected: 10},
+		{key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(",")}, expected: 0},
+		{key: tea.KeyMsg{Type: tea.KeyEnd}, expected: 10},
+		{key: tea.KeyMsg{Type: tea.KeyHome}, expected: 0},
+	}
+
+	for _, tc := range tt {
+		g.Update(tc.key)
+		assert.Equal(t, tc.expected, sg.Generation())
+	}
+
+	// Seeking can be undone.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.Equal(t, 10, sg.Generation())
+
+	// An edit removes the generations after it.
+	g.Update(tea.KeyMsg{Type: tea.KeyHome})
+	click(g, 0, headerHeight)
+	assert.Equal(t, "●"+strings.Repeat("─", 15)+" 0-0", timelineLine(g))
+}
+
+func TestGame_Paint(t *testing.T) {
+	sg := grid.New(10, 5)
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 20, Height: headerHeight + 5 + footerHeight})
+
+	// Dragging from a dead cell draws a line without gaps, and the spaces
+	// between the characters are painted over.
+	line := engine.Line(engine.Point{X: 0, Y: 0}, engine.Point{X: 6, Y: 3})
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 2*6 + 1, Y: headerHeight + 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+	g.Update(tea.MouseMsg{X: 2*6 + 1, Y: headerHeight + 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+	assert.ElementsMatch(t, line, engine.AliveCells(sg))
+
+	// Moving the mouse without a button does not paint.
+	g.Update(tea.MouseMsg{X: 2 * 9, Y: headerHeight + 4, Button: tea.MouseButtonNone, Action: tea.MouseActionMotion})
+	assert.ElementsMatch(t, line, engine.AliveCells(sg))
+
+	// Dragging from an alive cell erases the two last cells of the line.
+	g.Update(tea.MouseMsg{X: 2 * 6, Y: headerHeight + 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 2 * 3, Y: headerHeight + 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+	g.Update(tea.MouseMsg{X: 2 * 3, Y: headerHeight + 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+	assert.ElementsMatch(t, line[:5], engine.AliveCells(sg))
+
+	// The right button always erases.
+	g.Update(tea.MouseMsg{X: 2 * 9, Y: headerHeight, Button: tea.MouseButtonRight, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Button: tea.MouseButtonRight, Action: tea.MouseActionMotion})
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Button: tea.MouseButtonRight, Action: tea.MouseActionRelease})
+	assert.ElementsMatch(t, line[1:5], engine.AliveCells(sg))
+
+	// A painting is undone at once.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.ElementsMatch(t, line, engine.AliveCells(sg))
+}
+
+func TestGame_Cursor(t *testing.T) {
+	sg := grid.New(10, 5)
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 20, Height: headerHeight + 5 + footerHeight})
+
+	press := func(keys ...string) {
+		for _, k := range keys {
+			switch k {
+			case "left":
+				g.Update(tea.KeyMsg{Type: tea.KeyLeft})
+			default:
+				g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+			}
+		}
+	}
+
+	// The cursor is shown in the center of the viewport.
+	press("c", "x")
+	assert.Equal(t, []engine.Point{{X: 5, Y: 2}}, engine.AliveCells(sg))
+	assert.Contains(t, g.View(), "Cursor: 5,2")
+
+	// The count moves the cursor by several cells, and the pen draws the
+	// cells the cursor moves over. The cursor stays inside the grid.
+	press("3", "l", "d", "9", "j", "d")
+	assert.ElementsMatch(t, []engine.Point{{X: 5, Y: 2}, {X: 8, Y: 2}, {X: 8, Y: 3}, {X: 8, Y: 4}}, engine.AliveCells(sg))
+
+	press("e", "left", "1", "2")
+	assert.ElementsMatch(t, []engine.Point{{X: 5, Y: 2}, {X: 8, Y: 2}, {X: 8, Y: 3}}, engine.AliveCells(sg))
+	assert.Contains(t, g.View(), "Cursor: 7,4 erase 12")
+	assert.Equal(t, "□ □ □ □ □ □ □ ▢ □ □ ", gridLines(g)[4])
+
+	// The viewport is panned again when the cursor is hidden.
+	press("c", "h")
+	assert.NotContains(t, g.View(), "Cursor")
+	assert.Equal(t, "  □ □ □ □ □ ■ □ □ ■ ", gridLines(g)[2])
+}
+
+func TestGame_Selection(t *testing.T) {
+	sg := grid.New(10, 5)
+	clipboard := &bytes.Buffer{}
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}), game.WithSystemClipboard(clipboard))
+	g.Update(tea.WindowSizeMsg{Width: 20, Height: headerHeight + 5 + footerHeight})
+
+	press := func(keys ...string) {
+		for _, k := range keys {
+			g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+	}
+
+	sg.SetCell(1, 0, cell.Alive)
+	sg.SetCell(2, 1, cell.Alive)
+
+	// The cells are selected by dragging with the left button, and the
+	// selection is copied to the system clipboard as RLE.
+	press("m")
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 2 * 2, Y: headerHeight + 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+	g.Update(tea.MouseMsg{X: 2 * 2, Y: headerHeight + 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+	assert.Contains(t, g.View(), "Selection: 3x2")
+
+	press("C")
+	assert.Contains(t, g.View(), "Copied 3x2 cells.")
+	assert.Contains(t, clipboard.String(), "\033]52;c;")
+
+	// The ghost of the rotated clipboard follows the mouse, and a click
+	// stamps it.
+	press("R", "P")
+	assert.Contains(t, g.View(), "Paste: 2x3")
+	g.Update(tea.MouseMsg{X: 2 * 6, Y: headerHeight + 1, Button: tea.MouseButtonNone, Action: tea.MouseActionMotion})
+	assert.Equal(t, "□ □ □ □ □ □ □ ■ □ □ ", gridLines(g)[2])
+	assert.Equal(t, []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}}, engine.AliveCells(sg))
+
+	click(g, 2*6, headerHeight+1)
+	assert.Equal(t, []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 7, Y: 2}, {X: 6, Y: 3}}, engine.AliveCells(sg))
+
+	// The selection follows the keyboard cursor, and the selected cells are
+	// filled, cut or cleared.
+	press("P", "c", "m", "l", "j")
+	assert.Contains(t, g.View(), "Selection: 2x2")
+	press("F")
+	assert.Equal(t, []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 5, Y: 2}, {X: 6, Y: 2}, {X: 7, Y: 2}, {X: 5, Y: 3}, {X: 6, Y: 3}}, engine.AliveCells(sg))
+
+	press("X")
+	assert.Equal(t, []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 7, Y: 2}}, engine.AliveCells(sg))
+
+	press("u", "D")
+	assert.Equal(t, []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 7, Y: 2}}, engine.AliveCells(sg))
+
+	// The text pasted into the terminal is read as a pattern, which is
+	// flipped and stamped under the keyboard cursor.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x = 2, y = 2\n2o$o!"), Paste: true})
+	assert.Contains(t, g.View(), "Paste: 2x2")
+	press("V", "x")
+	assert.Equal(t, []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 7, Y: 2}, {X: 6, Y: 3}, {X: 6, Y: 4}, {X: 7, Y: 4}}, engine.AliveCells(sg))
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("not a pattern"), Paste: true})
+	assert.Contains(t, g.View(), "The pasted text is not a pattern")
+}
+
+func TestGame_Library(t *testing.T) {
+	sg := grid.New(20, 10)
+
+	g := game.New(20, 10, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 10 + footerHeight})
+
+	press := func(keys ...string) {
+		for _, k := range keys {
+			switch k {
+			case "enter":
+				g.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			case "esc":
+				g.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			default:
+				g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+			}
+		}
+	}
+
+	// The browser is shown instead of the cells, and it is closed without
+	// changing the cells.
+	press("L")
+	assert.Contains(t, g.View(), "Pattern library")
+	assert.Contains(t, g.View(), "Discovered by: unknown, 1969")
+	press("esc")
+	assert.NotContains(t, g.View(), "Pattern library")
+	assert.Empty(t, engine.AliveCells(sg))
+
+	// The selected pattern is placed under the cursor.
+	press("c", "L", "j", "j", "j", "j", "j", "j", "j", "j", "j", "j")
+	assert.Contains(t, g.View(), "Speed: c/4 diagonal")
+	press("enter")
+	assert.NotContains(t, g.View(), "Pattern library")
+	assert.Contains(t, g.View(), "Placed Glider.")
+	assert.Equal(t, []engine.Point{{X: 11, Y: 5}, {X: 12, Y: 6}, {X: 10, Y: 7}, {X: 11, Y: 7}, {X: 12, Y: 7}}, engine.AliveCells(sg))
+}
+
+func TestGame_Spaceship(t *testing.T) {
+	sg := grid.New(20, 10)
+	for _, p := range []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}} {
+		sg.SetCell(p.X, p.Y, cell.Alive)
+	}
+
+	g := game.New(20, 10, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 10 + footerHeight})
+
+	// The glider is found once it reappears at another position.
+	for range 4 {
+		g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	}
+
+	assert.Contains(t, g.View(), "Stabilised at generation 0 with period 4, moving at c/4 diagonal.")
+	assert.Contains(t, g.View(), "| Period: 4 | Speed: c/4 diagonal")
+}
+
+func TestGame_Stats(t *testing.T) {
+	sg := grid.New(10, 5)
+	for x := 1; x <= 3; x++ {
+		sg.SetCell(x, 2, cell.Alive)
+	}
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	g.Update(tea.WindowSizeMsg{Width: 60, Height: headerHeight + 5 + footerHeight})
+
+	// The cell added between the generations dies.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	sg.SetCell(9, 4, cell.Alive)
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+
+	// The panel is shown next to the cells, and the cells are not moved.
+	lines := gridLines(g)
+	assert.Contains(t, lines[0], "Statistics")
+	assert.Contains(t, lines[1], "Population: 3")
+	assert.Contains(t, lines[2], "Births:     2")
+	assert.Contains(t, lines[3], "Deaths:     3")
+	assert.Contains(t, g.View(), "Bounds:     3x1")
+	assert.Contains(t, g.View(), "Density:    100.0%")
+	assert.Contains(t, g.View(), "Population history:")
+	assert.Contains(t, g.View(), "▁▁ ")
+	assert.Contains(t, g.View(), "3 to 3")
+	assert.True(t, strings.HasPrefix(lines[2], "□ ■ ■ ■ □"))
+
+	// The bars are scaled between the lowest and the highest population.
+	for _, p := range []engine.Point{{X: 7, Y: 0}, {X: 8, Y: 0}, {X: 7, Y: 1}, {X: 8, Y: 1}} {
+		sg.SetCell(p.X, p.Y, cell.Alive)
+	}
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Contains(t, g.View(), "▁▁█ ")
+	assert.Contains(t, g.View(), "3 to 7")
+	assert.Contains(t, g.View(), "Bounds:     7x4")
+
+	// The bounds follow the painted cells and the undone changes.
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight + 4, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Contains(t, g.View(), "Bounds:     9x5")
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight + 4, Button: tea.MouseButtonNone, Action: tea.MouseActionRelease})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	assert.Contains(t, g.View(), "Bounds:     7x4")
+
+	// The births and the deaths of the last step are not shown after the
+	// cells are restored.
+	assert.Contains(t, g.View(), "Births:     -")
+	assert.Contains(t, g.View(), "Deaths:     -")
+
+	// The panel is hidden again.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	assert.NotContains(t, g.View(), "Statistics")
+}
+
+func TestGame_Help(t *testing.T) {
+	sg := grid.New(10, 5)
+
+	g := game.New(10, 5, game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 80, Height: headerHeight + 5 + footerHeight})
+	assert.Contains(t, g.View(), "Press '?' for help")
+	assert.NotContains(t, g.View(), "Show/hide help")
+
+	// The help is shown in place of the cells, so they cannot be clicked.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	lines := gridLines(g)
+	assert.True(t, strings.HasPrefix(lines[0], "? Show/hide help • ␣ Start/Pause"))
+	assert.Contains(t, g.View(), "i Statistics panel")
+	assert.NotContains(t, g.View(), "…")
+
+	g.Update(tea.MouseMsg{X: 0, Y: headerHeight, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	assert.Empty(t, engine.AliveCells(sg))
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	assert.NotContains(t, g.View(), "Show/hide help")
+	assert.Len(t, gridLines(g), 5)
+}
+
+func TestGame_Census(t *testing.T) {
+	sg := grid.New(20, 10)
+	for _, p := range []engine.Point{
+		{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2},
+		{X: 6, Y: 1}, {X: 7, Y: 1}, {X: 6, Y: 2}, {X: 7, Y: 2},
+		{X: 12, Y: 5}, {X: 12, Y: 6}, {X: 12, Y: 7},
+	} {
+		sg.SetCell(p.X, p.Y, cell.Alive)
+	}
+
+	path := filepath.Join(t.TempDir(), "census.csv")
+	g := game.New(20, 10, game.WithCensus(2, path), game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 80, Height: headerHeight + 10 + footerHeight})
+
+	// The table is shown instead of the cells, with the names of the
+	// library.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")})
+	assert.Contains(t, g.View(), "Counted 3 objects of 2 kinds.")
+	assert.Regexp(t, `2 +Block +Still life +1 +4 +xs4_33`, g.View())
+	assert.Regexp(t, `1 +Blinker +Oscillator +2 +3 +xp2_7`, g.View())
+
+	// The keys of the game do not change the cells.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, 0, sg.Generation())
+
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.Contains(t, g.View(), "Saved the census to "+path+".")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "count,name,kind,period,population,apgcode\n2,Block,Still life,1,4,xs4_33\n1,Blinker,Oscillator,2,3,xp2_7\n", string(data))
+
+	g.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.NotContains(t, g.View(), "apgcode")
+	assert.Contains(t, g.View(), "Generation: 0 |")
+}
+
+func TestGame_Soup(t *testing.T) {
+	newGame := func(opts ...game.Option) (*game.Game, *grid.Grid) {
+		sg := grid.New(20, 10)
+		opts = append(opts, game.WithEngine(func(width, height int) engine.Engine {
+			return sg
+		}))
+
+		g := game.New(20, 10, opts...)
+		g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 10 + footerHeight})
+
+		return g, sg
+	}
+
+	press := func(g *game.Game, keys ...string) {
+		for _, k := range keys {
+			g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+	}
+
+	// The soups get the following seeds, and the seed is shown.
+	g, sg := newGame(game.WithRandomCells(42, 0), game.WithSoup(6, soup.C1))
+	press(g, "S")
+	assert.Contains(t, g.View(), "Seed: 42")
+	press(g, "S")
+	assert.Contains(t, g.View(), "Seed: 43")
+
+	// The second soup is reproduced by its seed at startup. The soups fill
+	// the centered square.
+	other, otherGrid := newGame(game.WithRandomCells(43, 0.5), game.WithSoup(6, soup.C1))
+	assert.Contains(t, other.View(), "Seed: 43 | Density: 0.5 | Symmetry: C1 | Region: 6x6+7+2")
+	assert.Equal(t, engine.AliveCells(sg), engine.AliveCells(otherGrid))
+
+	for _, p := range engine.AliveCells(sg) {
+		assert.True(t, engine.Rect{MinX: 7, MinY: 2, MaxX: 13, MaxY: 8}.Contains(p.X, p.Y))
+	}
+
+	// The footer shows the settings of the last soup, not the changed ones.
+	press(g, "Y")
+	assert.Contains(t, g.View(), "Seed: 43 | Density: 0.5 | Symmetry: C1 | Region: 6x6+7+2")
+
+	// The symmetric soup is filled into the selection.
+	g, sg = newGame(game.WithRandomCells(44, 0))
+	press(g, "Y", "Y", "c", "m", "3", "l", "3", "j", "S")
+	assert.Contains(t, g.View(), "Filled 4x4 cells with a C4 soup of density 0.5 and seed 44.")
+	assert.Contains(t, g.View(), "Seed: 44 | Density: 0.5 | Symmetry: C4 | Region: 4x4+10+5")
+
+	// The soup of the selection is reproduced by its region at startup.
+	_, otherGrid = newGame(
+		game.WithRandomCells(44, 0.5),
+		game.WithSoup(0, soup.C4),
+		game.WithSoupRegion(engine.Rect{MinX: 10, MinY: 5, MaxX: 14, MaxY: 9}),
+	)
+	assert.Equal(t, engine.AliveCells(sg), engine.AliveCells(otherGrid))
+
+	rotated := make([]engine.Point, 0)
+	for _, p := range engine.AliveCells(sg) {
+		assert.True(t, engine.Rect{MinX: 10, MinY: 5, MaxX: 14, MaxY: 9}.Contains(p.X, p.Y))
+		rotated = append(rotated, engine.Point{X: 10 + 8 - p.Y, Y: 5 + p.X - 10})
+	}
+
+	assert.ElementsMatch(t, engine.AliveCells(sg), rotated)
+}
+
+func TestGame_SoupFitsTerminal(t *testing.T) {
+	newGame := func(density float64) (*game.Game, *grid.Grid) {
+		var sg *grid.Grid
+		g := game.New(20, 10, game.WithFitTerminal(), game.WithRandomCells(7, density), game.WithEngine(func(width, height int) engine.Engine {
+			sg = grid.New(width, height)
+			return sg
+		}))
+		g.Update(tea.WindowSizeMsg{Width: 80, Height: headerHeight + 15 + footerHeight})
+
+		return g, sg
+	}
+
+	// The soup at startup fills the same board as the soup filled in the
+	// game, so it is reproduced by the seed shown in the footer.
+	g, sg := newGame(0)
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	assert.Contains(t, g.View(), "Filled 40x15 cells with a C1 soup of density 0.5 and seed 7.")
+
+	_, startup := newGame(0.5)
+	assert.Equal(t, engine.AliveCells(sg), engine.AliveCells(startup))
+}
+
+func TestGame_AutoPause(t *testing.T) {
+	sg := grid.New(10, 5)
+	for x := 1; x <= 3; x++ {
+		sg.SetCell(x, 2, cell.Alive)
+	}
+
+	g := game.New(10, 5, game.WithTickInterval(time.Millisecond), game.WithEngine(func(width, height int) engine.Engine {
+		return sg
+	}))
+	g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 5 + footerHeight})
+
+	// The stepped blinker is detected without pausing.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Contains(t, g.View(), "Stabilised at generation 0 with period 2.")
+	assert.Contains(t, g.View(), "| Period: 2")
+
+	// An edit forgets the period.
+	g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	click(g, 14, headerHeight+4)
+	assert.NotContains(t, g.View(), "| Period:")
+	assert.Contains(t, g.View(), "| Auto-pause")
+
+	// The started game is paused once the cells become stable again, after
+	// the added cell dies.
+	_, cmd := g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+	cmd = cmd().(tea.BatchMsg)[0]
+
+	for range 10 {
+		if cmd == nil {
+			break
+		}
+
+		_, cmd = g.Update(cmd())
+	}
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, 5, sg.Generation())
+	assert.Contains(t, g.View(), "Stabilised at generation 3 with period 2.")
+}
+
+func TestGame_AutoPauseWithSpaceship(t *testing.T) {
+	tt := []struct {
+		name     string
+		topology grid.Topology
+		expected string
+	}{
+		{
+			name:     "the glider crashes into the corner of the plane and becomes a block",
+			topology: grid.Plane,
+			expected: "Stabilised at generation 23 with period 1.",
+		},
+		{
+			name:     "the glider moves around the torus",
+			topology: grid.Torus,
+			expected: "Stabilised at generation 0 with period 4, moving at c/4 diagonal.",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := grid.New(8, 8, grid.WithTopology(tc.topology))
+			for _, p := range []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}} {
+				sg.SetCell(p.X, p.Y, cell.Alive)
+			}
+
+			g := game.New(8, 8, game.WithAutoPause(), game.WithTickInterval(time.Millisecond), game.WithEngine(func(width, height int) engine.Engine {
+				return sg
+			}))
+			g.Update(tea.WindowSizeMsg{Width: 40, Height: headerHeight + 8 + footerHeight})
+
+			_, cmd := g.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+			cmd = cmd().(tea.BatchMsg)[0]
+
+			for range 100 {
+				if cmd == nil {
+					break
+				}
+
+				_, cmd = g.Update(cmd())
+			}
+
+			assert.Nil(t, cmd)
+			assert.Contains(t, g.View(), tc.expected)
+		})
+	}
+}
+
+// click presses and releases the left mouse button.
+func click(g *game.Game, x, y int) {
+	g.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+}
+
+// timelineLine returns the rendered timeline.
+func timelineLine(g *game.Game) string {
+	lines := strings.Split(g.View(), "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "Generation:") {
+			return lines[i-1]
+		}
+	}
+
+	return ""
+}
+
+// gridLines returns the rendered rows of cells without colors.
+func gridLines(g *game.Game) []string {
+	lines := strings.Split(g.View(), "\n")
+	rows := lines[headerHeight : len(lines)-footerHeight]
+
+	for i, row := range rows {
+		row = strings.ReplaceAll(row, "\033[32m", "")
+		row = strings.ReplaceAll(row, "\033[33m", "")
+		row = strings.ReplaceAll(row, "\033[35m", "")
+		row = strings.ReplaceAll(row, "\033[36m", "")
+		rows[i] = strings.ReplaceAll(row, "\033[0m", "")
+	}
+
+	return rows
+}
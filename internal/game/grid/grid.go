@@ -1,7 +1,17 @@
 package grid
 
 import (
+	"sync"
+
 	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+var (
+	_ engine.Resizable  = (*Grid)(nil)
+	_ engine.Rewindable = (*Grid)(nil)
+	_ engine.Counter    = (*Grid)(nil)
 )
 
 // Grid represents a grid of cells.
@@ -10,15 +20,85 @@ type Grid struct {
 	width      int
 	height     int
 	grid       [][]*cell.Cell
+	next       [][]*cell.Cell
+	rule       rule.Rule
+	topology   Topology
+	workers    int
+	// population, births, deaths and bounds are counted while the next
+	// generation is calculated, and the population and the bounds are
+	// updated by the edits. The bounds are found again only when an edit
+	// kills a cell on their border.
+	population  int
+	births      int
+	deaths      int
+	edited      bool
+	bounds      engine.Rect
+	boundsKnown bool
+}
+
+// counts are the numbers of cells counted in a stripe of rows, and the
+// bounds of the alive cells in it.
+type counts struct {
+	population int
+	births     int
+	deaths     int
+	bounds     engine.Rect
+}
+
+// Option configures the cell grid.
+type Option func(*Grid)
+
+// WithRule sets the rule used to calculate the next generation of the cells.
+// By default, Conway's rule (B3/S23) is used.
+func WithRule(r rule.Rule) Option {
+	return func(g *Grid) {
+		g.rule = r
+	}
+}
+
+// WithTopology sets how the edges of the cell grid are connected.
+// By default, the grid is a finite plane.
+func WithTopology(t Topology) Option {
+	return func(g *Grid) {
+		g.topology = t
+	}
+}
+
+// WithWorkers sets the number of goroutines that calculate the next
+// generation in parallel. Each goroutine calculates a horizontal stripe of
+// rows. By default, the next generation is calculated by a single goroutine.
+func WithWorkers(workers int) Option {
+	return func(g *Grid) {
+		g.workers = workers
+	}
 }
 
 // New creates a new cell grid with the given width and height.
-func New(width, height int) *Grid {
-	return &Grid{
-		width:  width,
-		height: height,
-		grid:   newEmptyGrid(width, height),
+func New(width, height int, opts ...Option) *Grid {
+	g := &Grid{
+		width:       width,
+		height:      height,
+		grid:        newEmptyGrid(width, height),
+		next:        newEmptyGrid(width, height),
+		rule:        rule.Conway,
+		boundsKnown: true,
+	}
+
+	for _, opt := range opts {
+		opt(g)
 	}
+
+	return g
+}
+
+// Rule returns the rule used to calculate the next generation of the cells.
+func (g *Grid) Rule() rule.Rule {
+	return g.rule
+}
+
+// Topology returns how the edges of the cell grid are connected.
+func (g *Grid) Topology() Topology {
+	return g.topology
 }
 
 // Generation returns the current generation of the cell grid.
@@ -26,38 +106,235 @@ func (g *Grid) Generation() int {
 	return g.generation
 }
 
-// State returns the current state of the cell grid.
+// SetGeneration sets the current generation of the cell grid without changing
+// the cells.
+func (g *Grid) SetGeneration(generation int) {
+	g.edited = g.edited || generation != g.generation
+	g.generation = generation
+}
+
+// Population returns the number of alive cells.
+func (g *Grid) Population() int {
+	return g.population
+}
+
+// Changes returns the number of cells born and the number of cells died in
+// the last generation. It returns false if the cell grid is edited since.
+func (g *Grid) Changes() (births, deaths int, ok bool) {
+	return g.births, g.deaths, !g.edited
+}
+
+// State returns the current state of the cell grid. The returned state is
+// reused by the grid, so it must not be kept between generations, and it
+// must not be changed.
 func (g *Grid) State() [][]*cell.Cell {
 	return g.grid
 }
 
+// Width returns the number of columns in the cell grid.
+func (g *Grid) Width() int {
+	return g.width
+}
+
+// Height returns the number of rows in the cell grid.
+func (g *Grid) Height() int {
+	return g.height
+}
+
+// Resize changes the width and height of the cell grid keeping the
+// generation and the cells in the same columns and rows. The cells outside
+// the new size are removed.
+func (g *Grid) Resize(width, height int) {
+	grid := newEmptyGrid(width, height)
+	for y := range min(height, g.height) {
+		copy(grid[y], g.grid[y][:min(width, g.width)])
+	}
+
+	g.width = width
+	g.height = height
+	g.grid = grid
+	g.next = newEmptyGrid(width, height)
+
+	// The removed cells are not known, so the remaining ones are counted.
+	g.population = 0
+	for y := range grid {
+		for x := range grid[y] {
+			if grid[y][x] == cell.Alive {
+				g.population++
+			}
+		}
+	}
+
+	g.edited = true
+	g.boundsKnown = false
+}
+
+// Cell returns the cell in the x-th column and y-th row.
+// The cells outside the grid are dead.
+func (g *Grid) Cell(x, y int) *cell.Cell {
+	if !g.contains(x, y) {
+		return cell.Dead
+	}
+
+	return g.grid[y][x]
+}
+
+// SetCell sets the cell in the x-th column and y-th row.
+// The cells outside the grid are ignored.
+func (g *Grid) SetCell(x, y int, c *cell.Cell) {
+	if !g.contains(x, y) {
+		return
+	}
+
+	g.count(x, y, g.grid[y][x], c)
+	g.grid[y][x] = c
+}
+
+// BoundingBox returns the smallest rectangle that contains all alive cells.
+// The cells are scanned only if an edit made the last bounds unknown.
+func (g *Grid) BoundingBox() engine.Rect {
+	if g.boundsKnown {
+		return g.bounds
+	}
+
+	g.bounds = engine.Rect{}
+
+	for y := range g.grid {
+		for x := range g.grid[y] {
+			if g.grid[y][x] == cell.Alive {
+				g.bounds = g.bounds.Union(engine.Rect{MinX: x, MinY: y, MaxX: x + 1, MaxY: y + 1})
+			}
+		}
+	}
+
+	g.boundsKnown = true
+
+	return g.bounds
+}
+
 // ToggleCell makes the cell alive or dead depending on the current state in the x-th column and y-th row.
+// The cells outside the grid are ignored.
 func (g *Grid) ToggleCell(x, y int) {
+	if !g.contains(x, y) {
+		return
+	}
+
 	if g.grid[y][x] == cell.Dead {
-		g.grid[y][x] = cell.Alive
+		g.SetCell(x, y, cell.Alive)
+		return
+	}
+
+	g.SetCell(x, y, cell.Dead)
+}
+
+// count updates the population and the bounds when the cell in the x-th
+// column and y-th row is replaced.
+func (g *Grid) count(x, y int, previous, c *cell.Cell) {
+	switch {
+	case previous != cell.Alive && c == cell.Alive:
+		g.population++
+		g.bounds = g.bounds.Union(engine.Rect{MinX: x, MinY: y, MaxX: x + 1, MaxY: y + 1})
+	case previous == cell.Alive && c != cell.Alive:
+		g.population--
+		g.boundsKnown = g.boundsKnown && !g.bounds.Border(x, y)
+	default:
 		return
 	}
 
-	g.grid[y][x] = cell.Dead
+	g.edited = true
 }
 
 // NextGeneration moves the cell grid to the next generation.
 func (g *Grid) NextGeneration() {
 	// We need to keep the state the same while we calculate the next generation.
-	// That's why we need a new grid.
-	nextGenerationGrid := newEmptyGrid(g.width, g.height)
+	// That's why we calculate it in the second grid and then swap the grids.
+	workers := min(g.workers, g.height)
+	if workers <= 1 {
+		g.setCounts(g.nextGenerationRows(0, g.height))
+	} else {
+		// Each goroutine reads the whole current grid, but writes only its own
+		// stripe of rows in the next grid and its own counts, so they do not
+		// race.
+		stripe := (g.height + workers - 1) / workers
+		stripes := make([]counts, (g.height+stripe-1)/stripe)
+
+		var wg sync.WaitGroup
+		for i := range stripes {
+			top := i * stripe
+			bottom := min(top+stripe, g.height)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stripes[i] = g.nextGenerationRows(top, bottom)
+			}()
+		}
+
+		wg.Wait()
+
+		g.setCounts(stripes...)
+	}
+
+	g.grid, g.next = g.next, g.grid
+	g.generation++
+	g.edited = false
+}
+
+// setCounts sums the counts of the stripes of rows of the next generation.
+func (g *Grid) setCounts(stripes ...counts) {
+	g.population, g.births, g.deaths, g.bounds = 0, 0, 0, engine.Rect{}
+
+	for _, c := range stripes {
+		g.population += c.population
+		g.births += c.births
+		g.deaths += c.deaths
+		g.bounds = g.bounds.Union(c.bounds)
+	}
+
+	g.boundsKnown = true
+}
+
+// nextGenerationRows calculates the next generation of the rows from top to
+// bottom (exclusive), and counts the alive, born and died cells in them.
+func (g *Grid) nextGenerationRows(top, bottom int) counts {
+	var c counts
+
+	for y := top; y < bottom; y++ {
+		// The bounds are extended once per row by its first and last alive
+		// cells.
+		first, last := -1, -1
 
-	for y := range g.grid {
 		for x := range g.grid[y] {
 			aliveNeighbors := g.countAliveNeighbors(x, y)
-			cell := g.grid[y][x]
-			nextGenerationCell := cell.NextGeneration(aliveNeighbors)
-			nextGenerationGrid[y][x] = nextGenerationCell
+			current := g.grid[y][x]
+			nextGenerationCell := current.NextGenerationWithRule(g.rule, aliveNeighbors)
+			g.next[y][x] = nextGenerationCell
+
+			switch alive := nextGenerationCell == cell.Alive; {
+			case alive && current != cell.Alive:
+				c.population++
+				c.births++
+			case alive:
+				c.population++
+			case current == cell.Alive:
+				c.deaths++
+			}
+
+			if nextGenerationCell == cell.Alive {
+				if first < 0 {
+					first = x
+				}
+
+				last = x
+			}
+		}
+
+		if first >= 0 {
+			c.bounds = c.bounds.Union(engine.Rect{MinX: first, MinY: y, MaxX: last + 1, MaxY: y + 1})
 		}
 	}
 
-	g.grid = nextGenerationGrid
-	g.generation++
+	return c
 }
 
 // countAliveNeighbors counts the number of alive neighbors of a cell.
@@ -70,16 +347,10 @@ func (g *Grid) countAliveNeighbors(x, y int) int {
 				continue
 			}
 
-			// Skip the cell if it's outside the grid.
-			// We consider them as dead cells.
-			ny := y + dy
-			nx := x + dx
-
-			if ny < 0 || ny >= len(g.grid) {
-				continue
-			}
-
-			if nx < 0 || nx >= len(g.grid[ny]) {
+			// Skip the cell if it's outside the grid and the edges are not
+			// connected. We consider them as dead cells.
+			nx, ny, ok := g.topology.Wrap(x+dx, y+dy, g.width, g.height)
+			if !ok {
 				continue
 			}
 
@@ -93,6 +364,11 @@ func (g *Grid) countAliveNeighbors(x, y int) int {
 	return aliveNeighbors
 }
 
+// contains returns true if the cell in the x-th column and y-th row is inside the grid.
+func (g *Grid) contains(x, y int) bool {
+	return x >= 0 && x < g.width && y >= 0 && y < g.height
+}
+
 // newEmptyGrid creates a new empty grid of cells with the given width and height.
 // All cells are dead in the beginning.
 func newEmptyGrid(width, height int) [][]*cell.Cell {
@@ -1,12 +1,16 @@
 package grid_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
 	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
 )
 
 func TestCellGrid_New(t *testing.T) {
@@ -62,6 +66,14 @@ func TestCellGrid_ToggleCell(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("ignore cells outside the grid", func(t *testing.T) {
+		for _, p := range []engine.Point{{X: -1, Y: 0}, {X: 0, Y: -1}, {X: width, Y: 0}, {X: 0, Y: height}} {
+			assert.NotPanics(t, func() { sg.ToggleCell(p.X, p.Y) })
+		}
+
+		assert.Equal(t, 0, sg.Population())
+	})
 }
 
 func TestCellGrid_NextGeneration(t *testing.T) {
@@ -253,3 +265,201 @@ func TestCellGrid_Generation(t *testing.T) {
 		assert.Equal(t, i, sg.Generation())
 	}
 }
+
+func TestCellGrid_NextGenerationWithRule(t *testing.T) {
+	// The cell in the middle has six alive neighbors.
+	addAliveCells := func(sg *grid.Grid) {
+		sg.ToggleCell(0, 0)
+		sg.ToggleCell(1, 0)
+		sg.ToggleCell(2, 0)
+		sg.ToggleCell(0, 2)
+		sg.ToggleCell(1, 2)
+		sg.ToggleCell(2, 2)
+	}
+
+	t.Run("the dead cell should be dead if there are six alive neighbors in Conway's rule", func(t *testing.T) {
+		sg := grid.New(3, 3)
+		addAliveCells(sg)
+		sg.NextGeneration()
+		assert.Equal(t, cell.Dead, sg.State()[1][1])
+	})
+
+	t.Run("the dead cell should be alive if there are six alive neighbors in HighLife", func(t *testing.T) {
+		sg := grid.New(3, 3, grid.WithRule(rule.HighLife))
+		addAliveCells(sg)
+		sg.NextGeneration()
+		assert.Equal(t, cell.Alive, sg.State()[1][1])
+	})
+}
+
+func TestCellGrid_SetCell(t *testing.T) {
+	sg := grid.New(3, 2)
+
+	sg.SetCell(2, 1, cell.Alive)
+	assert.Equal(t, cell.Alive, sg.Cell(2, 1))
+
+	// The cells outside the grid are ignored and always dead.
+	sg.SetCell(3, 1, cell.Alive)
+	sg.SetCell(-1, 0, cell.Alive)
+	assert.Equal(t, cell.Dead, sg.Cell(3, 1))
+	assert.Equal(t, cell.Dead, sg.Cell(-1, 0))
+
+	sg.SetCell(2, 1, cell.Dead)
+	assert.Equal(t, cell.Dead, sg.Cell(2, 1))
+	assert.Equal(t, 0, sg.Population())
+}
+
+func TestCellGrid_Changes(t *testing.T) {
+	sg := grid.New(5, 5)
+	for x := 1; x <= 3; x++ {
+		sg.SetCell(x, 2, cell.Alive)
+	}
+
+	// Setting an alive cell again does not count it twice.
+	sg.SetCell(2, 2, cell.Alive)
+	assert.Equal(t, 3, sg.Population())
+
+	// The blinker turns: two cells are born and two cells die.
+	sg.NextGeneration()
+	births, deaths, ok := sg.Changes()
+	assert.True(t, ok)
+	assert.Equal(t, 2, births)
+	assert.Equal(t, 2, deaths)
+	assert.Equal(t, 3, sg.Population())
+
+	// The changes are not known after an edit or a changed generation.
+	sg.ToggleCell(0, 0)
+	assert.Equal(t, 4, sg.Population())
+	_, _, ok = sg.Changes()
+	assert.False(t, ok)
+
+	sg.NextGeneration()
+	_, _, ok = sg.Changes()
+	assert.True(t, ok)
+
+	sg.SetGeneration(0)
+	_, _, ok = sg.Changes()
+	assert.False(t, ok)
+}
+
+func TestCellGrid_Resize(t *testing.T) {
+	sg := grid.New(4, 4)
+	sg.ToggleCell(1, 1)
+	sg.ToggleCell(3, 3)
+	sg.NextGeneration()
+	sg.ToggleCell(1, 1)
+	sg.ToggleCell(3, 3)
+
+	sg.Resize(6, 5)
+	assert.Equal(t, 6, sg.Width())
+	assert.Equal(t, 5, sg.Height())
+	assert.Equal(t, 1, sg.Generation())
+	assert.Equal(t, cell.Alive, sg.Cell(1, 1))
+	assert.Equal(t, cell.Alive, sg.Cell(3, 3))
+
+	sg.SetCell(5, 4, cell.Alive)
+	assert.Equal(t, cell.Alive, sg.Cell(5, 4))
+
+	// The cells outside the new size are removed.
+	sg.Resize(3, 2)
+	assert.Equal(t, []engine.Point{{X: 1, Y: 1}}, engine.AliveCells(sg))
+	assert.Equal(t, 1, sg.Population())
+
+	sg.Resize(6, 5)
+	assert.Equal(t, cell.Dead, sg.Cell(3, 3))
+
+	sg.NextGeneration()
+	assert.Equal(t, 2, sg.Generation())
+}
+
+func TestCellGrid_BoundingBox(t *testing.T) {
+	sg := grid.New(5, 5)
+	assert.True(t, sg.BoundingBox().Empty())
+
+	sg.ToggleCell(1, 3)
+	sg.ToggleCell(3, 2)
+	assert.Equal(t, engine.Rect{MinX: 1, MinY: 2, MaxX: 4, MaxY: 4}, sg.BoundingBox())
+
+	// The bounds shrink when a cell on their border dies, and follow the
+	// generations.
+	sg.ToggleCell(2, 2)
+	sg.ToggleCell(1, 3)
+	assert.Equal(t, engine.Rect{MinX: 2, MinY: 2, MaxX: 4, MaxY: 3}, sg.BoundingBox())
+
+	sg.NextGeneration()
+	assert.True(t, sg.BoundingBox().Empty())
+}
+
+func TestCellGrid_NextGenerationWithWorkers(t *testing.T) {
+	width := 50
+	height := 37
+
+	tt := []struct {
+		name     string
+		workers  int
+		topology grid.Topology
+	}{
+		{
+			name:     "two workers",
+			workers:  2,
+			topology: grid.Plane,
+		},
+		{
+			name:     "the number of rows is not divisible by the number of workers",
+			workers:  7,
+			topology: grid.Plane,
+		},
+		{
+			name:     "more workers than rows",
+			workers:  100,
+			topology: grid.Plane,
+		},
+		{
+			name:     "workers on the torus",
+			workers:  4,
+			topology: grid.Torus,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sequential := grid.New(width, height, grid.WithTopology(tc.topology))
+			parallel := grid.New(width, height, grid.WithTopology(tc.topology), grid.WithWorkers(tc.workers))
+			r := engine.Rect{MaxX: width, MaxY: height}
+			soup.Fill(sequential, r, 1, 0.5, soup.C1)
+			soup.Fill(parallel, r, 1, 0.5, soup.C1)
+
+			for range 50 {
+				sequential.NextGeneration()
+				parallel.NextGeneration()
+
+				assert.Equal(t, sequential.State(), parallel.State())
+				assert.Equal(t, sequential.Population(), parallel.Population())
+
+				births, deaths, _ := sequential.Changes()
+				parallelBirths, parallelDeaths, _ := parallel.Changes()
+				assert.Equal(t, births, parallelBirths)
+				assert.Equal(t, deaths, parallelDeaths)
+				assert.Equal(t, sequential.BoundingBox(), parallel.BoundingBox())
+			}
+
+			assert.Equal(t, sequential.Generation(), parallel.Generation())
+		})
+	}
+}
+
+func BenchmarkCellGrid_NextGeneration(b *testing.B) {
+	size := 512
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("%d workers", workers), func(b *testing.B) {
+			sg := grid.New(size, size, grid.WithWorkers(workers))
+			soup.Fill(sg, engine.Rect{MaxX: size, MaxY: size}, 1, 0.5, soup.C1)
+
+			b.ResetTimer()
+			for range b.N {
+				sg.NextGeneration()
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package grid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Topology defines how the edges of the cell grid are connected.
+type Topology int
+
+const (
+	// Plane is a finite plane. The cells outside the grid are considered to be dead.
+	Plane Topology = iota
+	// Torus connects the left edge to the right edge and the top edge to the bottom edge.
+	Torus
+	// KleinBottle connects the left edge to the right edge like a torus, but
+	// the top edge is connected to the bottom edge with a twist.
+	KleinBottle
+	// CrossSurface connects both pairs of the opposite edges with a twist.
+	// It is also known as the real projective plane.
+	CrossSurface
+)
+
+// String returns the name of the topology.
+func (t Topology) String() string {
+	switch t {
+	case Plane:
+		return "plane"
+	case Torus:
+		return "torus"
+	case KleinBottle:
+		return "klein"
+	case CrossSurface:
+		return "cross"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTopology parses the name of the topology as returned by String. The
+// name is case-insensitive.
+func ParseTopology(s string) (Topology, error) {
+	for _, t := range []Topology{Plane, Torus, KleinBottle, CrossSurface} {
+		if strings.EqualFold(strings.TrimSpace(s), t.String()) {
+			return t, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid topology %q: expected plane, torus, klein or cross", s)
+}
+
+// Wrap maps the x-th column and y-th row, which can be outside the grid with
+// the given width and height, to the cell inside the grid. It returns false if
+// there is no such cell.
+func (t Topology) Wrap(x, y, width, height int) (int, int, bool) {
+	insideX := x >= 0 && x < width
+	insideY := y >= 0 && y < height
+
+	if insideX && insideY {
+		return x, y, true
+	}
+
+	switch t {
+	case Torus:
+		return mod(x, width), mod(y, height), true
+	case KleinBottle:
+		// Crossing the top or bottom edge mirrors the column.
+		if !insideY {
+			x = width - 1 - x
+		}
+
+		return mod(x, width), mod(y, height), true
+	case CrossSurface:
+		// Crossing the left or right edge mirrors the row and crossing the top
+		// or bottom edge mirrors the column.
+		if !insideX {
+			y = height - 1 - y
+		}
+
+		if !insideY {
+			x = width - 1 - x
+		}
+
+		return mod(x, width), mod(y, height), true
+	default:
+		return 0, 0, false
+	}
+}
+
+// mod returns the non-negative remainder of a divided by b.
+func mod(a, b int) int {
+	return (a%b + b) % b
+}
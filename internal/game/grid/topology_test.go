@@ -0,0 +1,240 @@
+package grid_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+)
+
+func TestParseTopology(t *testing.T) {
+	for _, expected := range []grid.Topology{grid.Plane, grid.Torus, grid.KleinBottle, grid.CrossSurface} {
+		t.Run(expected.String(), func(t *testing.T) {
+			actual, err := grid.ParseTopology(expected.String())
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	actual, err := grid.ParseTopology(" Torus ")
+	require.NoError(t, err)
+	assert.Equal(t, grid.Torus, actual)
+
+	_, err = grid.ParseTopology("sphere")
+	assert.Error(t, err)
+}
+
+func TestTopology_Wrap(t *testing.T) {
+	width := 4
+	height := 3
+
+	tt := []struct {
+		name       string
+		topology   grid.Topology
+		x          int
+		y          int
+		expectedX  int
+		expectedY  int
+		expectedOK bool
+	}{
+		{
+			name:       "the cell inside the plane",
+			topology:   grid.Plane,
+			x:          1,
+			y:          2,
+			expectedX:  1,
+			expectedY:  2,
+			expectedOK: true,
+		},
+		{
+			name:       "the cell outside the plane",
+			topology:   grid.Plane,
+			x:          -1,
+			y:          2,
+			expectedOK: false,
+		},
+		{
+			name:       "the cell to the left of the torus",
+			topology:   grid.Torus,
+			x:          -1,
+			y:          1,
+			expectedX:  3,
+			expectedY:  1,
+			expectedOK: true,
+		},
+		{
+			name:       "the cell below the torus",
+			topology:   grid.Torus,
+			x:          1,
+			y:          3,
+			expectedX:  1,
+			expectedY:  0,
+			expectedOK: true,
+		},
+		{
+			name:       "the cell to the right of the Klein bottle",
+			topology:   grid.KleinBottle,
+			x:          4,
+			y:          1,
+			expectedX:  0,
+			expectedY:  1,
+			expectedOK: true,
+		},
+		{
+			name:       "the cell above the Klein bottle",
+			topology:   grid.KleinBottle,
+			x:          0,
+			y:          -1,
+			expectedX:  3,
+			expectedY:  2,
+			expectedOK: true,
+		},
+		{
+			name:       "the cell to the left of the cross-surface",
+			topology:   grid.CrossSurface,
+			x:          -1,
+			y:          0,
+			expectedX:  3,
+			expectedY:  2,
+			expectedOK: true,
+		},
+		{
+			name:       "the cell below the cross-surface",
+			topology:   grid.CrossSurface,
+			x:          1,
+			y:          3,
+			expectedX:  2,
+			expectedY:  0,
+			expectedOK: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			x, y, ok := tc.topology.Wrap(tc.x, tc.y, width, height)
+			assert.Equal(t, tc.expectedOK, ok)
+			if tc.expectedOK {
+				assert.Equal(t, tc.expectedX, x)
+				assert.Equal(t, tc.expectedY, y)
+			}
+		})
+	}
+}
+
+func TestCellGrid_GliderCrossesSeam(t *testing.T) {
+	// The glider moves one cell down and one cell right every four
+	// generations, so it moves by eight cells in both directions in 32
+	// generations.
+	generations := 32
+
+	glider := func(sg *grid.Grid) {
+		sg.ToggleCell(2, 3)
+		sg.ToggleCell(3, 4)
+		sg.ToggleCell(1, 5)
+		sg.ToggleCell(2, 5)
+		sg.ToggleCell(3, 5)
+	}
+
+	tt := []struct {
+		name      string
+		topology  grid.Topology
+		width     int
+		height    int
+		transform func(x, y int) (int, int)
+	}{
+		{
+			name:     "the glider returns to the same position after crossing both edges of the torus",
+			topology: grid.Torus,
+			width:    8,
+			height:   8,
+			transform: func(x, y int) (int, int) {
+				return x, y
+			},
+		},
+		{
+			name:     "the glider crosses the right edge of the Klein bottle",
+			topology: grid.KleinBottle,
+			width:    8,
+			height:   40,
+			transform: func(x, y int) (int, int) {
+				return x, y + 8
+			},
+		},
+		{
+			name:     "the glider is mirrored horizontally after crossing the bottom edge of the Klein bottle",
+			topology: grid.KleinBottle,
+			width:    40,
+			height:   8,
+			transform: func(x, y int) (int, int) {
+				return 40 - 1 - (x + 8), y
+			},
+		},
+		{
+			name:     "the glider is mirrored vertically after crossing the right edge of the cross-surface",
+			topology: grid.CrossSurface,
+			width:    8,
+			height:   40,
+			transform: func(x, y int) (int, int) {
+				return x, 40 - 1 - (y + 8)
+			},
+		},
+		{
+			name:     "the glider is mirrored horizontally after crossing the bottom edge of the cross-surface",
+			topology: grid.CrossSurface,
+			width:    40,
+			height:   8,
+			transform: func(x, y int) (int, int) {
+				return 40 - 1 - (x + 8), y
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sg := grid.New(tc.width, tc.height, grid.WithTopology(tc.topology))
+			glider(sg)
+
+			expected := grid.New(tc.width, tc.height)
+			for y, row := range sg.State() {
+				for x, c := range row {
+					if c == cell.Alive {
+						expected.ToggleCell(tc.transform(x, y))
+					}
+				}
+			}
+
+			for range generations {
+				sg.NextGeneration()
+			}
+
+			assert.Equal(t, expected.State(), sg.State())
+		})
+	}
+
+	t.Run("the glider turns into a block at the edge of the plane", func(t *testing.T) {
+		sg := grid.New(8, 8)
+		glider(sg)
+
+		for range generations {
+			sg.NextGeneration()
+		}
+
+		assert.Equal(t, 4, population(sg))
+	})
+}
+
+func population(sg *grid.Grid) int {
+	alive := 0
+	for _, row := range sg.State() {
+		for _, c := range row {
+			if c == cell.Alive {
+				alive++
+			}
+		}
+	}
+
+	return alive
+}
@@ -0,0 +1,587 @@
+package hashlife
+
+import (
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+const (
+	// minLevel is the level of the root node of an empty universe.
+	minLevel = 3
+	// defaultMaxNodes is the number of cached nodes that triggers the garbage collection.
+	defaultMaxNodes = 1 << 20
+)
+
+var (
+	_ engine.Quadtree   = (*Universe)(nil)
+	_ engine.Advancer   = (*Universe)(nil)
+	_ engine.Rewindable = (*Universe)(nil)
+)
+
+// node is a square of 2^level x 2^level cells in the quadtree. The nodes are
+// canonical, so equal squares are always represented by the same node, and
+// the nodes are never changed after they are created.
+type node struct {
+	nw         *node
+	ne         *node
+	sw         *node
+	se         *node
+	level      int
+	population int
+}
+
+// Level returns the level of the node.
+func (n *node) Level() int {
+	return n.level
+}
+
+// Population returns the number of alive cells in the node.
+func (n *node) Population() int {
+	return n.population
+}
+
+// nodeKey identifies a canonical node by its quadrants.
+type nodeKey struct {
+	nw *node
+	ne *node
+	sw *node
+	se *node
+}
+
+// resultKey identifies the memoised result of moving a node 2^step generations forward.
+type resultKey struct {
+	node *node
+	step int
+}
+
+// Universe represents an unbounded universe of cells stored in a memoised
+// quadtree. It can move the cells forward by an astronomically large number
+// of generations at once, if the pattern is regular enough.
+type Universe struct {
+	generation int
+	rule       rule.Rule
+	maxNodes   int
+
+	root       *node
+	deadLeaf   *node
+	aliveLeaf  *node
+	emptyNodes []*node
+	nodes      map[nodeKey]*node
+	results    map[resultKey]*node
+}
+
+// Option configures the universe.
+type Option func(*Universe)
+
+// WithRule sets the rule used to calculate the next generation of the cells.
+// By default, Conway's rule (B3/S23) is used.
+func WithRule(r rule.Rule) Option {
+	return func(u *Universe) {
+		u.rule = r
+	}
+}
+
+// WithMaxNodes sets the number of cached nodes that triggers the garbage
+// collection of the nodes that are no longer used. The limit is raised after
+// the collection if the used nodes alone take more than half of it.
+func WithMaxNodes(maxNodes int) Option {
+	return func(u *Universe) {
+		u.maxNodes = maxNodes
+	}
+}
+
+// New creates a new empty universe. It panics if the rule makes the dead cells
+// without alive neighbors alive (B0), because the universe would be infinitely
+// populated.
+func New(opts ...Option) *Universe {
+	u := &Universe{
+		rule:      rule.Conway,
+		maxNodes:  defaultMaxNodes,
+		deadLeaf:  &node{},
+		aliveLeaf: &node{population: 1},
+		nodes:     make(map[nodeKey]*node),
+		results:   make(map[resultKey]*node),
+	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	if u.rule.Born(0) {
+		panic("hashlife: rules with B0 are not supported by the unbounded universe")
+	}
+
+	u.root = u.emptyNode(minLevel)
+
+	return u
+}
+
+// Rule returns the rule used to calculate the next generation of the cells.
+func (u *Universe) Rule() rule.Rule {
+	return u.rule
+}
+
+// Generation returns the current generation of the universe.
+func (u *Universe) Generation() int {
+	return u.generation
+}
+
+// SetGeneration sets the current generation of the universe without changing
+// the cells.
+func (u *Universe) SetGeneration(generation int) {
+	u.generation = generation
+}
+
+// Population returns the number of alive cells.
+func (u *Universe) Population() int {
+	return u.root.population
+}
+
+// Leaf returns the canonical node of 8x8 cells.
+func (u *Universe) Leaf(rows [8]uint8) engine.Node {
+	n := u.emptyNode(engine.LeafLevel)
+
+	for y, row := range rows {
+		for x := range 8 {
+			if row&(1<<x) != 0 {
+				n = u.set(n, x, y, true)
+			}
+		}
+	}
+
+	return n
+}
+
+// Join returns the canonical node with the given quadrants. It panics if the
+// quadrants are not created by the universe or their levels differ.
+func (u *Universe) Join(nw, ne, sw, se engine.Node) engine.Node {
+	q := [4]*node{nw.(*node), ne.(*node), sw.(*node), se.(*node)}
+	for _, n := range q[1:] {
+		if n.level != q[0].level {
+			panic("hashlife: the quadrants of the node must have the same level")
+		}
+	}
+
+	return u.join(q[0], q[1], q[2], q[3])
+}
+
+// EmptyNode returns the canonical node without alive cells at the given level.
+func (u *Universe) EmptyNode(level int) engine.Node {
+	return u.emptyNode(max(level, engine.LeafLevel))
+}
+
+// Quadrants returns the quadrants of the node.
+func (u *Universe) Quadrants(n engine.Node) (nw, ne, sw, se engine.Node) {
+	q := n.(*node)
+	return q.nw, q.ne, q.sw, q.se
+}
+
+// Rows returns the cells of the node of 8x8 cells.
+func (u *Universe) Rows(n engine.Node) [8]uint8 {
+	var rows [8]uint8
+
+	for y := range rows {
+		for x := range 8 {
+			if u.alive(n.(*node), x, y) {
+				rows[y] |= 1 << x
+			}
+		}
+	}
+
+	return rows
+}
+
+// Root returns the root node, which is centered around the origin.
+func (u *Universe) Root() engine.Node {
+	return u.root
+}
+
+// SetRoot replaces all cells with the node centered around the origin and
+// sets the current generation. The node must be created by the universe.
+func (u *Universe) SetRoot(n engine.Node, generation int) {
+	u.root = n.(*node)
+	u.generation = generation
+}
+
+// Nodes returns the number of nodes in the cache.
+func (u *Universe) Nodes() int {
+	return len(u.nodes)
+}
+
+// MaxNodes returns the number of cached nodes that triggers the next garbage
+// collection.
+func (u *Universe) MaxNodes() int {
+	return u.maxNodes
+}
+
+// Cell returns the cell in the x-th column and y-th row.
+func (u *Universe) Cell(x, y int) *cell.Cell {
+	half := 1 << (u.root.level - 1)
+	if x < -half || x >= half || y < -half || y >= half {
+		return cell.Dead
+	}
+
+	if u.alive(u.root, x+half, y+half) {
+		return cell.Alive
+	}
+
+	return cell.Dead
+}
+
+// SetCell sets the cell in the x-th column and y-th row.
+func (u *Universe) SetCell(x, y int, c *cell.Cell) {
+	for {
+		half := 1 << (u.root.level - 1)
+		if x >= -half && x < half && y >= -half && y < half {
+			u.root = u.set(u.root, x+half, y+half, c == cell.Alive)
+			return
+		}
+
+		// The dead cells outside the root are already dead.
+		if c == cell.Dead {
+			return
+		}
+
+		u.expand()
+	}
+}
+
+// BoundingBox returns the smallest rectangle that contains all alive cells.
+func (u *Universe) BoundingBox() engine.Rect {
+	half := 1 << (u.root.level - 1)
+	bounds := u.boundingBox(u.root, make(map[*node]engine.Rect))
+
+	return engine.Rect{
+		MinX: bounds.MinX - half,
+		MinY: bounds.MinY - half,
+		MaxX: bounds.MaxX - half,
+		MaxY: bounds.MaxY - half,
+	}
+}
+
+// NextGeneration moves the universe to the next generation.
+func (u *Universe) NextGeneration() {
+	u.Advance(1)
+}
+
+// Advance moves the universe the given number of generations forward. The
+// number is split into powers of two, and the universe is moved by each of
+// them with a single lookup in the memoised quadtree.
+func (u *Universe) Advance(generations int) {
+	for step := 0; generations > 0; step++ {
+		if generations&1 == 1 {
+			u.step(step)
+		}
+
+		generations >>= 1
+	}
+}
+
+// GC removes the nodes and the memoised results that are no longer reachable
+// from the current state of the universe.
+func (u *Universe) GC() {
+	reachable := make(map[*node]struct{}, len(u.nodes))
+	u.mark(u.root, reachable)
+
+	for _, n := range u.emptyNodes {
+		u.mark(n, reachable)
+	}
+
+	for key, n := range u.nodes {
+		if _, ok := reachable[n]; !ok {
+			delete(u.nodes, key)
+		}
+	}
+
+	for key, result := range u.results {
+		_, nodeReachable := reachable[key.node]
+		_, resultReachable := reachable[result]
+
+		if !nodeReachable || !resultReachable {
+			delete(u.results, key)
+		}
+	}
+}
+
+// step moves the universe 2^step generations forward.
+func (u *Universe) step(step int) {
+	// The pattern must stay inside the result, which is the central half of
+	// the root. It moves at most one cell per generation, so the pattern must
+	// be inside the central quarter, and the root must be large enough.
+	for u.root.level < step+3 || !u.isCentered() {
+		u.expand()
+	}
+
+	u.root = u.successor(u.root, step)
+	u.generation += 1 << step
+
+	if len(u.nodes) > u.maxNodes {
+		u.GC()
+
+		// The used nodes of a large pattern may take most of the limit, so it
+		// is raised to keep room for the cache, otherwise every step would
+		// collect the garbage without freeing much.
+		u.maxNodes = max(u.maxNodes, 2*len(u.nodes))
+	}
+}
+
+// isCentered returns true if all alive cells are inside the central quarter of the root.
+func (u *Universe) isCentered() bool {
+	r := u.root
+	central := r.nw.se.se.population + r.ne.sw.sw.population + r.sw.ne.ne.population + r.se.nw.nw.population
+
+	return central == r.population
+}
+
+// expand doubles the size of the root keeping the cells in the center.
+func (u *Universe) expand() {
+	r := u.root
+	e := u.emptyNode(r.level - 1)
+
+	u.root = u.join(
+		u.join(e, e, e, r.nw),
+		u.join(e, e, r.ne, e),
+		u.join(e, r.sw, e, e),
+		u.join(r.se, e, e, e),
+	)
+}
+
+// join returns the canonical node with the given quadrants.
+func (u *Universe) join(nw, ne, sw, se *node) *node {
+	key := nodeKey{nw: nw, ne: ne, sw: sw, se: se}
+	if n, ok := u.nodes[key]; ok {
+		return n
+	}
+
+	n := &node{
+		nw:         nw,
+		ne:         ne,
+		sw:         sw,
+		se:         se,
+		level:      nw.level + 1,
+		population: nw.population + ne.population + sw.population + se.population,
+	}
+	u.nodes[key] = n
+
+	return n
+}
+
+// emptyNode returns the canonical node without alive cells at the given level.
+func (u *Universe) emptyNode(level int) *node {
+	if len(u.emptyNodes) == 0 {
+		u.emptyNodes = append(u.emptyNodes, u.deadLeaf)
+	}
+
+	for len(u.emptyNodes) <= level {
+		e := u.emptyNodes[len(u.emptyNodes)-1]
+		u.emptyNodes = append(u.emptyNodes, u.join(e, e, e, e))
+	}
+
+	return u.emptyNodes[level]
+}
+
+// alive returns true if the cell in the x-th column and y-th row of the node is alive.
+func (u *Universe) alive(n *node, x, y int) bool {
+	for n.level > 0 {
+		if n.population == 0 {
+			return false
+		}
+
+		half := 1 << (n.level - 1)
+		n, x, y = quadrant(n, x, y, half)
+	}
+
+	return n.population == 1
+}
+
+// set returns the node with the cell in the x-th column and y-th row changed.
+func (u *Universe) set(n *node, x, y int, alive bool) *node {
+	if n.level == 0 {
+		if alive {
+			return u.aliveLeaf
+		}
+
+		return u.deadLeaf
+	}
+
+	half := 1 << (n.level - 1)
+	nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+
+	switch {
+	case x < half && y < half:
+		nw = u.set(nw, x, y, alive)
+	case y < half:
+		ne = u.set(ne, x-half, y, alive)
+	case x < half:
+		sw = u.set(sw, x, y-half, alive)
+	default:
+		se = u.set(se, x-half, y-half, alive)
+	}
+
+	return u.join(nw, ne, sw, se)
+}
+
+// boundingBox returns the smallest rectangle that contains all alive cells of
+// the node relative to its top left corner.
+func (u *Universe) boundingBox(n *node, memo map[*node]engine.Rect) engine.Rect {
+	if n.population == 0 {
+		return engine.Rect{}
+	}
+
+	if n.level == 0 {
+		return engine.Rect{MaxX: 1, MaxY: 1}
+	}
+
+	if bounds, ok := memo[n]; ok {
+		return bounds
+	}
+
+	half := 1 << (n.level - 1)
+	bounds := u.boundingBox(n.nw, memo).
+		Union(offset(u.boundingBox(n.ne, memo), half, 0)).
+		Union(offset(u.boundingBox(n.sw, memo), 0, half)).
+		Union(offset(u.boundingBox(n.se, memo), half, half))
+	memo[n] = bounds
+
+	return bounds
+}
+
+// successor returns the central half of the node moved 2^step generations
+// forward. The step is limited by level-2, because the cells outside the node
+// would affect the central half after more generations.
+func (u *Universe) successor(n *node, step int) *node {
+	if n.population == 0 {
+		return n.nw
+	}
+
+	step = min(step, n.level-2)
+
+	key := resultKey{node: n, step: step}
+	if result, ok := u.results[key]; ok {
+		return result
+	}
+
+	var result *node
+	if n.level == 2 {
+		result = u.life4x4(n)
+	} else {
+		result = u.successorOfQuadrants(n, step)
+	}
+
+	u.results[key] = result
+
+	return result
+}
+
+// successorOfQuadrants calculates the successor of the node from nine
+// overlapping subnodes of half the size.
+func (u *Universe) successorOfQuadrants(n *node, step int) *node {
+	nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+
+	// The nine subnodes are arranged in a 3x3 layout:
+	//
+	//	s1 s2 s3
+	//	s4 s5 s6
+	//	s7 s8 s9
+	s1 := u.successor(nw, step)
+	s2 := u.successor(u.join(nw.ne, ne.nw, nw.se, ne.sw), step)
+	s3 := u.successor(ne, step)
+	s4 := u.successor(u.join(nw.sw, nw.se, sw.nw, sw.ne), step)
+	s5 := u.successor(u.join(nw.se, ne.sw, sw.ne, se.nw), step)
+	s6 := u.successor(u.join(ne.sw, ne.se, se.nw, se.ne), step)
+	s7 := u.successor(sw, step)
+	s8 := u.successor(u.join(sw.ne, se.nw, sw.se, se.sw), step)
+	s9 := u.successor(se, step)
+
+	if step < n.level-2 {
+		// The subnodes are already moved by the required number of
+		// generations, so we need only their central parts.
+		return u.join(
+			u.join(s1.se, s2.sw, s4.ne, s5.nw),
+			u.join(s2.se, s3.sw, s5.ne, s6.nw),
+			u.join(s4.se, s5.sw, s7.ne, s8.nw),
+			u.join(s5.se, s6.sw, s8.ne, s9.nw),
+		)
+	}
+
+	// The subnodes are moved by half of the generations, so we move the
+	// quadrants combined from them by another half.
+	return u.join(
+		u.successor(u.join(s1, s2, s4, s5), step),
+		u.successor(u.join(s2, s3, s5, s6), step),
+		u.successor(u.join(s4, s5, s7, s8), step),
+		u.successor(u.join(s5, s6, s8, s9), step),
+	)
+}
+
+// life4x4 calculates the next generation of the central 2x2 cells of the 4x4 node.
+func (u *Universe) life4x4(n *node) *node {
+	var cells [4][4]bool
+	for y := range 4 {
+		for x := range 4 {
+			cells[y][x] = u.alive(n, x, y)
+		}
+	}
+
+	next := func(x, y int) *node {
+		aliveNeighbors := 0
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if (dx != 0 || dy != 0) && cells[y+dy][x+dx] {
+					aliveNeighbors++
+				}
+			}
+		}
+
+		if u.rule.Next(cells[y][x], aliveNeighbors) {
+			return u.aliveLeaf
+		}
+
+		return u.deadLeaf
+	}
+
+	return u.join(next(1, 1), next(2, 1), next(1, 2), next(2, 2))
+}
+
+// mark adds the node and all its descendants to the set of reachable nodes.
+func (u *Universe) mark(n *node, reachable map[*node]struct{}) {
+	if n.level == 0 {
+		return
+	}
+
+	if _, ok := reachable[n]; ok {
+		return
+	}
+
+	reachable[n] = struct{}{}
+
+	u.mark(n.nw, reachable)
+	u.mark(n.ne, reachable)
+	u.mark(n.sw, reachable)
+	u.mark(n.se, reachable)
+}
+
+// quadrant returns the quadrant of the node that contains the cell in the
+// x-th column and y-th row, and the coordinates of the cell in the quadrant.
+func quadrant(n *node, x, y, half int) (*node, int, int) {
+	switch {
+	case x < half && y < half:
+		return n.nw, x, y
+	case y < half:
+		return n.ne, x - half, y
+	case x < half:
+		return n.sw, x, y - half
+	default:
+		return n.se, x - half, y - half
+	}
+}
+
+// offset moves the rectangle by the given number of columns and rows.
+func offset(r engine.Rect, dx, dy int) engine.Rect {
+	if r.Empty() {
+		return r
+	}
+
+	return engine.Rect{MinX: r.MinX + dx, MinY: r.MinY + dy, MaxX: r.MaxX + dx, MaxY: r.MaxY + dy}
+}
@@ -0,0 +1,243 @@
+package hashlife_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/hashlife"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+// soupRect is the square centered around the origin that is filled with the
+// random soups.
+var soupRect = engine.Rect{MinX: -8, MinY: -8, MaxX: 8, MaxY: 8}
+
+func TestUniverse_SetCell(t *testing.T) {
+	u := hashlife.New()
+	assert.Equal(t, cell.Dead, u.Cell(-1000, 1000))
+
+	u.SetCell(-1000, 1000, cell.Alive)
+	u.SetCell(3, -7, cell.Alive)
+	assert.Equal(t, cell.Alive, u.Cell(-1000, 1000))
+	assert.Equal(t, cell.Alive, u.Cell(3, -7))
+	assert.Equal(t, 2, u.Population())
+	assert.Equal(t, engine.Rect{MinX: -1000, MinY: -7, MaxX: 4, MaxY: 1001}, u.BoundingBox())
+
+	u.SetCell(-1000, 1000, cell.Dead)
+	assert.Equal(t, cell.Dead, u.Cell(-1000, 1000))
+	assert.Equal(t, 1, u.Population())
+	assert.Equal(t, engine.Rect{MinX: 3, MinY: -7, MaxX: 4, MaxY: -6}, u.BoundingBox())
+}
+
+func TestUniverse_NextGeneration(t *testing.T) {
+	tt := []struct {
+		name string
+		rule rule.Rule
+		seed uint64
+	}{
+		{
+			name: "random soup in Conway's rule",
+			rule: rule.Conway,
+			seed: 1,
+		},
+		{
+			name: "another random soup in Conway's rule",
+			rule: rule.Conway,
+			seed: 2,
+		},
+		{
+			name: "random soup in HighLife",
+			rule: rule.HighLife,
+			seed: 3,
+		},
+		{
+			name: "random soup in a rule where the alive cells without neighbors survive",
+			rule: rule.MustParse("B3/S012345678"),
+			seed: 4,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			u := hashlife.New(hashlife.WithRule(tc.rule))
+			naive := sparse.New(sparse.WithRule(tc.rule))
+			soup.Fill(u, soupRect, tc.seed, 0.5, soup.C1)
+			soup.Fill(naive, soupRect, tc.seed, 0.5, soup.C1)
+
+			for range 100 {
+				u.NextGeneration()
+				naive.NextGeneration()
+
+				assert.Equal(t, engine.AliveCells(naive), engine.AliveCells(u))
+			}
+
+			assert.Equal(t, 100, u.Generation())
+		})
+	}
+}
+
+func TestUniverse_Advance(t *testing.T) {
+	tt := []struct {
+		name        string
+		seed        uint64
+		generations int
+	}{
+		{
+			name:        "advance by one generation",
+			seed:        5,
+			generations: 1,
+		},
+		{
+			name:        "advance by a power of two",
+			seed:        6,
+			generations: 64,
+		},
+		{
+			name:        "advance by a number that is not a power of two",
+			seed:        7,
+			generations: 300,
+		},
+		{
+			name:        "advance by a large number of generations",
+			seed:        8,
+			generations: 1000,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			u := hashlife.New()
+			naive := sparse.New()
+			soup.Fill(u, soupRect, tc.seed, 0.5, soup.C1)
+			soup.Fill(naive, soupRect, tc.seed, 0.5, soup.C1)
+
+			u.Advance(tc.generations)
+			for range tc.generations {
+				naive.NextGeneration()
+			}
+
+			assert.Equal(t, tc.generations, u.Generation())
+			assert.Equal(t, naive.Population(), u.Population())
+			assert.Equal(t, engine.AliveCells(naive), engine.AliveCells(u))
+		})
+	}
+}
+
+func TestUniverse_AdvanceGliderAstronomically(t *testing.T) {
+	// The glider moves one cell down and one cell right every four generations.
+	u := hashlife.New()
+	u.SetCell(1, 0, cell.Alive)
+	u.SetCell(2, 1, cell.Alive)
+	u.SetCell(0, 2, cell.Alive)
+	u.SetCell(1, 2, cell.Alive)
+	u.SetCell(2, 2, cell.Alive)
+
+	generations := 1 << 40
+	u.Advance(generations)
+
+	distance := generations / 4
+	assert.Equal(t, generations, u.Generation())
+	assert.Equal(t, 5, u.Population())
+	assert.Equal(t, engine.Rect{MinX: distance, MinY: distance, MaxX: distance + 3, MaxY: distance + 3}, u.BoundingBox())
+}
+
+func TestUniverse_GC(t *testing.T) {
+	u := hashlife.New()
+	naive := sparse.New()
+	soup.Fill(u, soupRect, 9, 0.5, soup.C1)
+	soup.Fill(naive, soupRect, 9, 0.5, soup.C1)
+
+	u.Advance(500)
+	before := u.Nodes()
+
+	u.GC()
+	assert.Less(t, u.Nodes(), before)
+
+	// The universe must keep working correctly after the garbage collection.
+	u.Advance(500)
+	for range 1000 {
+		naive.NextGeneration()
+	}
+
+	assert.Equal(t, engine.AliveCells(naive), engine.AliveCells(u))
+}
+
+func TestUniverse_GCWithMaxNodes(t *testing.T) {
+	maxNodes := 1000
+	u := hashlife.New(hashlife.WithMaxNodes(maxNodes))
+	naive := sparse.New()
+	soup.Fill(u, soupRect, 10, 0.5, soup.C1)
+	soup.Fill(naive, soupRect, 10, 0.5, soup.C1)
+
+	for range 200 {
+		u.NextGeneration()
+		naive.NextGeneration()
+	}
+
+	assert.Equal(t, engine.AliveCells(naive), engine.AliveCells(u))
+	assert.LessOrEqual(t, u.Nodes(), 2*maxNodes)
+}
+
+func TestUniverse_GCRaisesMaxNodes(t *testing.T) {
+	u := hashlife.New(hashlife.WithMaxNodes(1))
+	soup.Fill(u, soupRect, 11, 0.5, soup.C1)
+	assert.Equal(t, 1, u.MaxNodes())
+
+	// The used nodes do not fit into the limit, so it is raised after the
+	// collection to keep room for the cache.
+	u.NextGeneration()
+	assert.GreaterOrEqual(t, u.MaxNodes(), 2*u.Nodes())
+
+	raised := u.MaxNodes()
+	u.GC()
+	assert.Equal(t, raised, u.MaxNodes())
+}
+
+func TestUniverse_Quadtree(t *testing.T) {
+	u := hashlife.New()
+
+	// The glider in the top left corner of the leaf.
+	rows := [8]uint8{0b010, 0b100, 0b111}
+	leaf := u.Leaf(rows)
+	assert.Equal(t, engine.LeafLevel, leaf.Level())
+	assert.Equal(t, 5, leaf.Population())
+	assert.Equal(t, rows, u.Rows(leaf))
+	assert.Same(t, leaf, u.Leaf(rows))
+
+	empty := u.EmptyNode(engine.LeafLevel)
+	root := u.Join(empty, empty, empty, leaf)
+	u.SetRoot(root, 42)
+
+	assert.Same(t, root, u.Root())
+	assert.Equal(t, 42, u.Generation())
+	assert.Equal(t, engine.Rect{MinX: 0, MinY: 0, MaxX: 3, MaxY: 3}, u.BoundingBox())
+
+	nw, ne, sw, se := u.Quadrants(root)
+	assert.Same(t, empty, nw)
+	assert.Same(t, empty, ne)
+	assert.Same(t, empty, sw)
+	assert.Same(t, leaf, se)
+
+	u.Advance(4)
+	assert.Equal(t, 46, u.Generation())
+	assert.Equal(t, engine.Rect{MinX: 1, MinY: 1, MaxX: 4, MaxY: 4}, u.BoundingBox())
+}
+
+func TestUniverse_JoinPanicsWithDifferentLevels(t *testing.T) {
+	u := hashlife.New()
+
+	assert.Panics(t, func() {
+		u.Join(u.EmptyNode(3), u.EmptyNode(3), u.EmptyNode(3), u.EmptyNode(4))
+	})
+}
+
+func TestNew_PanicsWithB0Rule(t *testing.T) {
+	assert.Panics(t, func() {
+		hashlife.New(hashlife.WithRule(rule.MustParse("B03/S23")))
+	})
+}
@@ -0,0 +1,190 @@
+// Package history records the states of the cells, so the cells can be moved
+// back and forward through them.
+package history
+
+import (
+	"slices"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+// frame is a recorded state of the cells. Only the cells that differ from the
+// previous state are stored.
+type frame struct {
+	generation int
+	changed    []engine.Point
+}
+
+// History is a bounded list of the recorded states of the cells. The states
+// are stored as the differences between them, so the history takes little
+// memory when few cells change between the states.
+type History struct {
+	limit   int
+	frames  []frame
+	current int
+	start   int
+	alive   map[engine.Point]struct{}
+}
+
+// New creates an empty history that keeps the given number of the latest
+// states.
+func New(limit int) *History {
+	h := &History{limit: max(limit, 1)}
+	h.Clear()
+
+	return h
+}
+
+// Clear removes all recorded states.
+func (h *History) Clear() {
+	h.frames = nil
+	h.current = -1
+	h.start = -1
+	h.alive = make(map[engine.Point]struct{})
+}
+
+// Len returns the number of recorded states.
+func (h *History) Len() int {
+	return len(h.frames)
+}
+
+// Current returns the index of the current state, or -1 if nothing is
+// recorded.
+func (h *History) Current() int {
+	return h.current
+}
+
+// Record adds the state of the cells of the engine after the current state.
+// The states after the current one are removed, so they can no longer be
+// redone, and the oldest state is removed when the history is full. Nothing
+// is recorded if neither the cells nor the generation has changed.
+func (h *History) Record(e engine.Engine) {
+	alive := make(map[engine.Point]struct{}, len(h.alive))
+
+	var changed []engine.Point
+	for _, p := range engine.AliveCells(e) {
+		alive[p] = struct{}{}
+		if _, ok := h.alive[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+
+	for p := range h.alive {
+		if _, ok := alive[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+
+	if h.current >= 0 && len(changed) == 0 && h.frames[h.current].generation == e.Generation() {
+		return
+	}
+
+	// The first state is never applied, so its cells are not stored.
+	if h.current < 0 {
+		changed = nil
+	}
+
+	h.frames = append(h.frames[:h.current+1], frame{generation: e.Generation(), changed: changed})
+	h.current++
+	h.alive = alive
+
+	if h.start >= h.current {
+		h.start = -1
+	}
+
+	if len(h.frames) > h.limit {
+		h.frames = slices.Delete(h.frames, 0, 1)
+		h.frames[0].changed = nil
+		h.current--
+		h.start = max(h.start-1, -1)
+	}
+}
+
+// MarkStart remembers the current state as the one the cells are rewound to.
+func (h *History) MarkStart() {
+	h.start = h.current
+}
+
+// Undo moves the cells of the engine to the previous state. It returns false
+// if there is no previous state.
+func (h *History) Undo(e engine.Engine) bool {
+	if h.current <= 0 {
+		return false
+	}
+
+	h.moveTo(e, h.current-1)
+
+	return true
+}
+
+// Redo moves the cells of the engine to the next state. It returns false if
+// there is no next state.
+func (h *History) Redo(e engine.Engine) bool {
+	if h.current >= len(h.frames)-1 {
+		return false
+	}
+
+	h.moveTo(e, h.current+1)
+
+	return true
+}
+
+// Rewind moves the cells of the engine to the marked state. It returns false
+// if no state is marked, or the marked state is the current one or is no
+// longer recorded.
+func (h *History) Rewind(e engine.Engine) bool {
+	if h.start < 0 || h.start == h.current {
+		return false
+	}
+
+	h.moveTo(e, h.start)
+
+	return true
+}
+
+// Back moves the cells of the engine to the latest state of the previous
+// generations, so the edits made in that generation are kept. It returns
+// false if no previous generation is recorded.
+func (h *History) Back(e engine.Engine) bool {
+	for i := h.current - 1; i >= 0; i-- {
+		if h.frames[i].generation < h.frames[h.current].generation {
+			h.moveTo(e, i)
+			return true
+		}
+	}
+
+	return false
+}
+
+// moveTo applies the differences between the current state and the i-th one
+// to the cells of the engine, and sets the generation of the i-th state if
+// the engine supports it.
+func (h *History) moveTo(e engine.Engine, i int) {
+	for h.current > i {
+		h.apply(e, h.frames[h.current].changed)
+		h.current--
+	}
+
+	for h.current < i {
+		h.current++
+		h.apply(e, h.frames[h.current].changed)
+	}
+
+	if r, ok := e.(engine.Rewindable); ok {
+		r.SetGeneration(h.frames[i].generation)
+	}
+}
+
+// apply toggles the cells in the engine and in the alive cells of the
+// current state.
+func (h *History) apply(e engine.Engine, changed []engine.Point) {
+	for _, p := range changed {
+		engine.Toggle(e, p.X, p.Y)
+
+		if _, ok := h.alive[p]; ok {
+			delete(h.alive, p)
+		} else {
+			h.alive[p] = struct{}{}
+		}
+	}
+}
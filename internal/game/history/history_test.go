@@ -0,0 +1,148 @@
+package history_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/history"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+var (
+	horizontal = []engine.Point{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}
+	vertical   = []engine.Point{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}}
+)
+
+func TestHistory_UndoRedo(t *testing.T) {
+	u := sparse.New()
+	h := history.New(10)
+	h.Record(u)
+
+	for _, p := range horizontal {
+		u.SetCell(p.X, p.Y, cell.Alive)
+		h.Record(u)
+	}
+
+	u.NextGeneration()
+	h.Record(u)
+	assert.Equal(t, 5, h.Len())
+
+	assert.True(t, h.Undo(u))
+	assert.Equal(t, 0, u.Generation())
+	assert.Equal(t, horizontal, engine.AliveCells(u))
+
+	assert.True(t, h.Undo(u))
+	assert.Equal(t, horizontal[:2], engine.AliveCells(u))
+
+	assert.True(t, h.Redo(u))
+	assert.True(t, h.Redo(u))
+	assert.False(t, h.Redo(u))
+	assert.Equal(t, 1, u.Generation())
+	assert.Equal(t, vertical, engine.AliveCells(u))
+
+	for h.Undo(u) {
+	}
+	assert.Empty(t, engine.AliveCells(u))
+	assert.Equal(t, 0, h.Current())
+
+	// A new state removes the states that could be redone.
+	u.SetCell(5, 5, cell.Alive)
+	h.Record(u)
+	assert.Equal(t, 2, h.Len())
+	assert.False(t, h.Redo(u))
+}
+
+func TestHistory_RecordUnchanged(t *testing.T) {
+	u := sparse.New()
+	u.SetCell(0, 0, cell.Alive)
+	u.SetCell(1, 0, cell.Alive)
+	u.SetCell(0, 1, cell.Alive)
+	u.SetCell(1, 1, cell.Alive)
+
+	h := history.New(10)
+	h.Record(u)
+	h.Record(u)
+	assert.Equal(t, 1, h.Len())
+
+	// The generations of a still life are recorded without cells.
+	u.NextGeneration()
+	h.Record(u)
+	assert.Equal(t, 2, h.Len())
+}
+
+func TestHistory_Rewind(t *testing.T) {
+	u := sparse.New()
+	h := history.New(10)
+	assert.False(t, h.Rewind(u))
+
+	for _, p := range horizontal {
+		u.SetCell(p.X, p.Y, cell.Alive)
+	}
+	h.Record(u)
+	h.MarkStart()
+
+	for range 3 {
+		u.NextGeneration()
+		h.Record(u)
+	}
+
+	assert.True(t, h.Rewind(u))
+	assert.Equal(t, 0, u.Generation())
+	assert.Equal(t, horizontal, engine.AliveCells(u))
+	assert.False(t, h.Rewind(u))
+}
+
+func TestHistory_Back(t *testing.T) {
+	u := sparse.New()
+	h := history.New(10)
+
+	for _, p := range horizontal {
+		u.SetCell(p.X, p.Y, cell.Alive)
+	}
+	h.Record(u)
+
+	u.NextGeneration()
+	h.Record(u)
+
+	// The edits of the first generation are kept when going back from the
+	// second one.
+	u.SetCell(5, 5, cell.Alive)
+	h.Record(u)
+	u.NextGeneration()
+	h.Record(u)
+
+	assert.True(t, h.Back(u))
+	assert.Equal(t, 1, u.Generation())
+	assert.Equal(t, append(vertical, engine.Point{X: 5, Y: 5}), engine.AliveCells(u))
+
+	assert.True(t, h.Back(u))
+	assert.Equal(t, 0, u.Generation())
+	assert.Equal(t, horizontal, engine.AliveCells(u))
+
+	assert.False(t, h.Back(u))
+}
+
+func TestHistory_Limit(t *testing.T) {
+	u := sparse.New()
+	u.SetCell(0, 0, cell.Alive)
+
+	h := history.New(3)
+	h.Record(u)
+	h.MarkStart()
+
+	for x := 1; x <= 3; x++ {
+		u.SetCell(x, 0, cell.Alive)
+		h.Record(u)
+	}
+
+	// The oldest state is removed together with the mark.
+	assert.Equal(t, 3, h.Len())
+	assert.False(t, h.Rewind(u))
+
+	for h.Undo(u) {
+	}
+	assert.Equal(t, []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}, engine.AliveCells(u))
+}
@@ -0,0 +1,164 @@
+package history
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+// keyframe is a generation with all alive cells stored.
+type keyframe struct {
+	generation int
+	cells      []engine.Point
+	// hashes are the hashes of the cells of the keyframe and the following
+	// generations recorded one by one. The generations skipped after them
+	// are recomputed, but not hashed.
+	hashes []uint64
+}
+
+// Timeline records a range of generations of the cells, so any of them can be
+// restored. Only a generation in every interval is stored as a keyframe, and
+// the generations between the keyframes are recomputed from the keyframe
+// before them. The other generations are remembered by the hashes of their
+// cells, so the timeline knows when the cells are changed by an edit.
+type Timeline struct {
+	interval  int
+	limit     int
+	keyframes []keyframe
+}
+
+// NewTimeline creates an empty timeline that stores a keyframe every interval
+// generations and keeps the given number of the latest keyframes.
+func NewTimeline(interval, limit int) *Timeline {
+	return &Timeline{interval: max(interval, 1), limit: max(limit, 1)}
+}
+
+// Clear removes all recorded generations.
+func (t *Timeline) Clear() {
+	t.keyframes = nil
+}
+
+// Empty returns true if no generation is recorded.
+func (t *Timeline) Empty() bool {
+	return len(t.keyframes) == 0
+}
+
+// First returns the first recorded generation.
+func (t *Timeline) First() int {
+	if t.Empty() {
+		return 0
+	}
+
+	return t.keyframes[0].generation
+}
+
+// Last returns the last recorded generation.
+func (t *Timeline) Last() int {
+	if t.Empty() {
+		return 0
+	}
+
+	last := t.keyframes[len(t.keyframes)-1]
+
+	return last.generation + len(last.hashes) - 1
+}
+
+// Record adds the current generation of the engine to the timeline. If the
+// generation is already recorded with other cells, the cells are changed by
+// an edit, so the generations after it are removed. The generations skipped
+// since the last recorded one are recomputed from the keyframe before them.
+func (t *Timeline) Record(e engine.Engine) {
+	generation := e.Generation()
+	cells := engine.AliveCells(e)
+	sum := engine.Hash(cells)
+
+	switch {
+	case t.Empty() || generation < t.First():
+		t.Clear()
+	case generation <= t.Last():
+		k := t.keyframes[t.keyframeAt(generation)]
+		if i := generation - k.generation; i < len(k.hashes) && k.hashes[i] == sum {
+			return
+		}
+
+		t.truncate(generation)
+	case generation == t.Last()+1:
+		last := &t.keyframes[len(t.keyframes)-1]
+		if generation-last.generation < t.interval {
+			last.hashes = append(last.hashes, sum)
+			return
+		}
+	}
+
+	t.insert(keyframe{generation: generation, cells: cells, hashes: []uint64{sum}})
+}
+
+// Seek moves the cells of the engine to the recorded generation. The cells
+// are restored from the keyframe before the generation and recomputed from
+// it. It returns false if the generation is not recorded or the generation of
+// the engine cannot be set.
+func (t *Timeline) Seek(e engine.Engine, generation int) bool {
+	if t.Empty() || generation < t.First() || generation > t.Last() {
+		return false
+	}
+
+	r, ok := e.(engine.Rewindable)
+	if !ok {
+		return false
+	}
+
+	k := t.keyframes[t.keyframeAt(generation)]
+
+	for _, p := range engine.AliveCells(e) {
+		e.SetCell(p.X, p.Y, cell.Dead)
+	}
+
+	for _, p := range k.cells {
+		e.SetCell(p.X, p.Y, cell.Alive)
+	}
+
+	r.SetGeneration(k.generation)
+	engine.Advance(e, generation-k.generation)
+
+	// The skipped generation becomes a keyframe, so it is known whether the
+	// cells are edited after seeking.
+	if generation-k.generation >= len(k.hashes) {
+		cells := engine.AliveCells(e)
+		t.insert(keyframe{generation: generation, cells: cells, hashes: []uint64{engine.Hash(cells)}})
+	}
+
+	return true
+}
+
+// keyframeAt returns the index of the last keyframe at or before the
+// recorded generation.
+func (t *Timeline) keyframeAt(generation int) int {
+	return sort.Search(len(t.keyframes), func(i int) bool {
+		return t.keyframes[i].generation > generation
+	}) - 1
+}
+
+// insert adds the keyframe keeping the keyframes sorted, and removes the
+// oldest keyframe if there are too many of them.
+func (t *Timeline) insert(k keyframe) {
+	t.keyframes = slices.Insert(t.keyframes, t.keyframeAt(k.generation)+1, k)
+
+	if len(t.keyframes) > t.limit {
+		t.keyframes = slices.Delete(t.keyframes, 0, 1)
+	}
+}
+
+// truncate removes the generation and the generations after it.
+func (t *Timeline) truncate(generation int) {
+	i := sort.Search(len(t.keyframes), func(i int) bool {
+		return t.keyframes[i].generation >= generation
+	})
+	t.keyframes = t.keyframes[:i]
+
+	if i > 0 {
+		last := &t.keyframes[i-1]
+		last.hashes = last.hashes[:min(len(last.hashes), generation-last.generation)]
+	}
+}
@@ -0,0 +1,144 @@
+package history_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/history"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+// addGlider adds a glider moving down and to the right.
+func addGlider(e engine.Engine) {
+	for _, p := range []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}} {
+		e.SetCell(p.X, p.Y, cell.Alive)
+	}
+}
+
+// shifted returns the points moved by the given number of columns and rows.
+func shifted(points []engine.Point, dx, dy int) []engine.Point {
+	result := make([]engine.Point, len(points))
+	for i, p := range points {
+		result[i] = engine.Point{X: p.X + dx, Y: p.Y + dy}
+	}
+
+	return result
+}
+
+func TestTimeline_Seek(t *testing.T) {
+	u := sparse.New()
+	addGlider(u)
+	glider := engine.AliveCells(u)
+
+	tl := history.NewTimeline(8, 100)
+	assert.True(t, tl.Empty())
+	tl.Record(u)
+
+	for range 40 {
+		u.NextGeneration()
+		tl.Record(u)
+	}
+
+	assert.Equal(t, 0, tl.First())
+	assert.Equal(t, 40, tl.Last())
+
+	// The glider moves by one cell every four generations.
+	tt := []struct {
+		generation int
+		expected   []engine.Point
+	}{
+		{generation: 0, expected: glider},
+		{generation: 12, expected: shifted(glider, 3, 3)},
+		{generation: 37, expected: engine.AliveCells(recomputed(37))},
+		{generation: 40, expected: shifted(glider, 10, 10)},
+	}
+
+	for _, tc := range tt {
+		assert.True(t, tl.Seek(u, tc.generation))
+		assert.Equal(t, tc.generation, u.Generation())
+		assert.Equal(t, tc.expected, engine.AliveCells(u))
+	}
+
+	assert.False(t, tl.Seek(u, 41))
+	assert.False(t, tl.Seek(u, -1))
+}
+
+func TestTimeline_Edit(t *testing.T) {
+	u := sparse.New()
+	addGlider(u)
+
+	tl := history.NewTimeline(8, 100)
+	tl.Record(u)
+
+	for range 20 {
+		u.NextGeneration()
+		tl.Record(u)
+	}
+
+	// Seeking to a recorded generation keeps the generations after it.
+	tl.Seek(u, 10)
+	tl.Record(u)
+	assert.Equal(t, 20, tl.Last())
+
+	// The generations after an edit are removed, and the edited cells are
+	// restored by seeking.
+	u.SetCell(20, 20, cell.Alive)
+	tl.Record(u)
+	assert.Equal(t, 10, tl.Last())
+
+	edited := engine.AliveCells(u)
+	tl.Seek(u, 0)
+	tl.Seek(u, 10)
+	assert.Equal(t, edited, engine.AliveCells(u))
+}
+
+func TestTimeline_SkippedGenerations(t *testing.T) {
+	u := sparse.New()
+	addGlider(u)
+
+	tl := history.NewTimeline(8, 100)
+	tl.Record(u)
+
+	engine.Advance(u, 100)
+	tl.Record(u)
+	assert.Equal(t, 100, tl.Last())
+
+	// The skipped generations are recomputed.
+	assert.True(t, tl.Seek(u, 50))
+	assert.Equal(t, engine.AliveCells(recomputed(50)), engine.AliveCells(u))
+
+	tl.Record(u)
+	assert.Equal(t, 100, tl.Last())
+}
+
+func TestTimeline_Limit(t *testing.T) {
+	g := grid.New(5, 5, grid.WithTopology(grid.Torus))
+	addGlider(g)
+
+	tl := history.NewTimeline(10, 3)
+	tl.Record(g)
+
+	for range 50 {
+		g.NextGeneration()
+		tl.Record(g)
+	}
+
+	// Only the last three keyframes are kept.
+	assert.Equal(t, 30, tl.First())
+	assert.Equal(t, 50, tl.Last())
+	assert.False(t, tl.Seek(g, 29))
+	assert.True(t, tl.Seek(g, 30))
+}
+
+// recomputed returns the glider after the given number of generations.
+func recomputed(generations int) engine.Engine {
+	u := sparse.New()
+	addGlider(u)
+	engine.Advance(u, generations)
+
+	return u
+}
@@ -6,18 +6,77 @@ import "github.com/charmbracelet/bubbles/key"
 type keyMap struct {
 	ToggleStartPause key.Binding
 	Reset            key.Binding
+	Save             key.Binding
 	Quit             key.Binding
+	Up               key.Binding
+	Down             key.Binding
+	Left             key.Binding
+	Right            key.Binding
+	ZoomIn           key.Binding
+	ZoomOut          key.Binding
+	FitPattern       key.Binding
+	CenterOnOrigin   key.Binding
+	SwitchRenderer   key.Binding
+	Step             key.Binding
+	Skip             key.Binding
+	Faster           key.Binding
+	Slower           key.Binding
+	IncreaseSkip     key.Binding
+	DecreaseSkip     key.Binding
+	Undo             key.Binding
+	Redo             key.Binding
+	Rewind           key.Binding
+	Back             key.Binding
+	SeekBackward     key.Binding
+	SeekForward      key.Binding
+	SeekFirst        key.Binding
+	SeekLast         key.Binding
+	ToggleCursor     key.Binding
+	ToggleCell       key.Binding
+	Draw             key.Binding
+	Erase            key.Binding
+	Count            key.Binding
+	Select           key.Binding
+	Copy             key.Binding
+	Cut              key.Binding
+	Clear            key.Binding
+	Fill             key.Binding
+	Paste            key.Binding
+	Rotate           key.Binding
+	FlipHorizontal   key.Binding
+	FlipVertical     key.Binding
+	Library          key.Binding
+	Soup             key.Binding
+	Symmetry         key.Binding
+	AutoPause        key.Binding
+	Census           key.Binding
+	Stats            key.Binding
+	Help             key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view.
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.ToggleStartPause, k.Reset, k.Quit}
+	return []key.Binding{k.Help, k.ToggleStartPause, k.ToggleCursor, k.Reset, k.Save, k.Quit}
 }
 
-// FullHelp returns keybindings for the expanded help view.
+// FullHelp returns keybindings for the expanded help view. Each group fits
+// into a line of an 80 columns wide terminal.
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.ToggleStartPause, k.Reset, k.Quit},
+		{k.Help, k.ToggleStartPause, k.Reset, k.Save, k.Quit},
+		{k.Up, k.Down},
+		{k.Left, k.Right},
+		{k.ZoomIn, k.ZoomOut, k.FitPattern, k.CenterOnOrigin, k.SwitchRenderer},
+		{k.Step, k.Skip, k.Faster, k.Slower, k.IncreaseSkip, k.DecreaseSkip},
+		{k.Undo, k.Redo, k.Rewind, k.Back},
+		{k.SeekBackward, k.SeekForward, k.SeekFirst, k.SeekLast},
+		{k.ToggleCursor, k.ToggleCell, k.Draw},
+		{k.Erase, k.Count},
+		{k.Select, k.Copy, k.Cut, k.Clear},
+		{k.Fill, k.Paste, k.Rotate},
+		{k.FlipHorizontal, k.FlipVertical},
+		{k.Library, k.Soup, k.Symmetry},
+		{k.AutoPause, k.Census, k.Stats},
 	}
 }
 
@@ -30,8 +89,188 @@ var gameKeys = keyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "Reset"),
 	),
+	Save: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "Save"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "esc", "ctrl+c"),
 		key.WithHelp("q", "Quit"),
 	),
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "Pan or move cursor up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "Pan or move cursor down"),
+	),
+	Left: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "Pan or move cursor left"),
+	),
+	Right: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "Pan or move cursor right"),
+	),
+	ZoomIn: key.NewBinding(
+		key.WithKeys("+", "="),
+		key.WithHelp("+", "Zoom in"),
+	),
+	ZoomOut: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "Zoom out"),
+	),
+	FitPattern: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "Fit pattern"),
+	),
+	CenterOnOrigin: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "Centre on origin"),
+	),
+	SwitchRenderer: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "Switch view"),
+	),
+	Step: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "Step"),
+	),
+	Skip: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "Skip generations"),
+	),
+	Faster: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "Faster"),
+	),
+	Slower: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "Slower"),
+	),
+	IncreaseSkip: key.NewBinding(
+		key.WithKeys("}"),
+		key.WithHelp("}", "Skip more"),
+	),
+	DecreaseSkip: key.NewBinding(
+		key.WithKeys("{"),
+		key.WithHelp("{", "Skip less"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "Undo"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("U", "ctrl+r"),
+		key.WithHelp("U", "Redo"),
+	),
+	Rewind: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "Rewind to start"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "Previous generation"),
+	),
+	SeekBackward: key.NewBinding(
+		key.WithKeys(","),
+		key.WithHelp(",", "Seek backward"),
+	),
+	SeekForward: key.NewBinding(
+		key.WithKeys("."),
+		key.WithHelp(".", "Seek forward"),
+	),
+	SeekFirst: key.NewBinding(
+		key.WithKeys("home"),
+		key.WithHelp("home", "Seek to first"),
+	),
+	SeekLast: key.NewBinding(
+		key.WithKeys("end"),
+		key.WithHelp("end", "Seek to last"),
+	),
+	ToggleCursor: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "Show/hide cursor"),
+	),
+	ToggleCell: key.NewBinding(
+		key.WithKeys("x", "enter"),
+		key.WithHelp("x", "Toggle cell under cursor"),
+	),
+	Draw: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "Draw with cursor"),
+	),
+	Erase: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "Erase with cursor"),
+	),
+	Count: key.NewBinding(
+		key.WithKeys("0", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("0-9", "Count of cells to move cursor by"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "Select cells"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "Copy selection"),
+	),
+	Cut: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "Cut selection"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "Clear selection"),
+	),
+	Fill: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "Fill selection"),
+	),
+	Paste: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "Paste"),
+	),
+	Rotate: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "Rotate clipboard"),
+	),
+	FlipHorizontal: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "Flip clipboard horizontally"),
+	),
+	FlipVertical: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "Flip clipboard vertically"),
+	),
+	Library: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "Pattern library"),
+	),
+	Soup: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "Fill random soup"),
+	),
+	Symmetry: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "Switch soup symmetry"),
+	),
+	AutoPause: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "Auto-pause when stable"),
+	),
+	Census: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "Census of the objects"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "Statistics panel"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "Show/hide help"),
+	),
 }
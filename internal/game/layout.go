@@ -0,0 +1,122 @@
+package game
+
+import (
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/render"
+)
+
+const (
+	// footerHeight is the number of lines below the grid: the timeline, the
+	// generation and the status.
+	footerHeight = 3
+)
+
+// header is the title and the hint rendered above the grid. The keys are
+// listed in the help shown in place of the cells.
+var header = []string{
+	"============================ Conway's Game of Life ============================",
+	"Press '?' for help, '␣' to start/pause, 'q' to quit. Draw with the mouse.      ",
+	"===============================================================================",
+}
+
+// layout describes where the screen cells are rendered in the terminal.
+type layout struct {
+	// top is the terminal row of the first line of characters.
+	top int
+	// columns is the number of columns of screen cells.
+	columns int
+	// rows is the number of rows of screen cells.
+	rows int
+	// renderer draws the screen cells with characters.
+	renderer render.Renderer
+}
+
+// layout returns the current layout of the viewport.
+func (g *Game) layout() layout {
+	return layout{top: len(header), columns: g.viewport.Width, rows: g.viewport.Height, renderer: g.renderer}
+}
+
+// width returns the number of terminal columns taken by the characters.
+func (l layout) width() int {
+	scaleX, _ := l.renderer.Scale()
+
+	return (l.columns + scaleX - 1) / scaleX * l.renderer.CharWidth()
+}
+
+// lines returns the number of lines of characters.
+func (l layout) lines() int {
+	_, scaleY := l.renderer.Scale()
+
+	return (l.rows + scaleY - 1) / scaleY
+}
+
+// screenCellAt returns the screen cell under the terminal column and row. It
+// returns false if there is no screen cell there, including the spaces
+// after the characters that take several columns. If a character draws
+// several screen cells, the top left one is returned, because the mouse
+// position is known up to a character.
+func (l layout) screenCellAt(x, y int) (int, int, bool) {
+	charWidth := l.renderer.CharWidth()
+	if x < 0 || y < l.top || x%charWidth != 0 {
+		return 0, 0, false
+	}
+
+	scaleX, scaleY := l.renderer.Scale()
+
+	column, row := x/charWidth*scaleX, (y-l.top)*scaleY
+	if column >= l.columns || row >= l.rows {
+		return 0, 0, false
+	}
+
+	return column, row, true
+}
+
+// screenCellAtChar returns the screen cell drawn by the character under the
+// terminal column and row, including the space after the character. The
+// screen cell can be outside the viewport.
+func (l layout) screenCellAtChar(x, y int) (int, int) {
+	scaleX, scaleY := l.renderer.Scale()
+
+	return x / l.renderer.CharWidth() * scaleX, (y - l.top) * scaleY
+}
+
+// terminalCapacity returns the number of columns and rows of screen cells
+// that fit into the terminal. It is at least one character.
+func (g *Game) terminalCapacity() (int, int) {
+	scaleX, scaleY := g.renderer.Scale()
+
+	columns := max((g.terminalWidth-g.statsWidth())/g.renderer.CharWidth(), 1)
+	rows := max(g.terminalHeight-len(header)-footerHeight, 1)
+
+	return columns * scaleX, rows * scaleY
+}
+
+// resize changes the size of the game to fit the terminal. The bounded
+// engines never become smaller than the alive cells, so they are preserved,
+// and the cells that do not fit into the terminal can be reached by panning.
+func (g *Game) resize() {
+	columns, rows := g.terminalCapacity()
+
+	resizable, ok := g.universe.(engine.Resizable)
+	if !ok {
+		g.width, g.height = columns, rows
+		return
+	}
+
+	bounds := resizable.BoundingBox()
+	g.width = max(columns, bounds.MaxX)
+	g.height = max(rows, bounds.MaxY)
+
+	resizable.Resize(g.width, g.height)
+}
+
+// truncate cuts the line to the given number of runes, so it is not wrapped
+// by the terminal.
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return line
+	}
+
+	return string(runes[:width])
+}
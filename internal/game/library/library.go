@@ -0,0 +1,115 @@
+// Package library provides the classic patterns embedded into the binary.
+package library
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+)
+
+//go:embed patterns/*.rle
+var files embed.FS
+
+// Category is the kind of the pattern by how it evolves.
+type Category int
+
+const (
+	// StillLife does not change.
+	StillLife Category = iota
+	// Oscillator returns to its initial state after the period.
+	Oscillator
+	// Spaceship returns to its initial state after the period, but moved.
+	Spaceship
+	// Gun emits spaceships forever.
+	Gun
+	// Puffer moves and leaves debris behind.
+	Puffer
+	// Methuselah evolves for a long time before it stabilizes.
+	Methuselah
+)
+
+// String returns the name of the category.
+func (c Category) String() string {
+	switch c {
+	case StillLife:
+		return "Still life"
+	case Oscillator:
+		return "Oscillator"
+	case Spaceship:
+		return "Spaceship"
+	case Gun:
+		return "Gun"
+	case Puffer:
+		return "Puffer"
+	case Methuselah:
+		return "Methuselah"
+	default:
+		return fmt.Sprintf("Category(%d)", int(c))
+	}
+}
+
+// Entry is a pattern of the library with what is known about it.
+type Entry struct {
+	// Pattern is the pattern with its name and discoverer.
+	Pattern *pattern.Pattern
+	// Category is the kind of the pattern.
+	Category Category
+	// Period is the number of generations after which the pattern repeats
+	// itself. It is zero for the methuselahs.
+	Period int
+	// Speed is the speed of the spaceships and the puffers, like "c/4
+	// diagonal". It is empty for the other patterns.
+	Speed string
+}
+
+// catalog describes the embedded pattern files in the order they are listed.
+var catalog = []struct {
+	file     string
+	category Category
+	period   int
+	speed    string
+}{
+	{file: "block.rle", category: StillLife, period: 1},
+	{file: "beehive.rle", category: StillLife, period: 1},
+	{file: "loaf.rle", category: StillLife, period: 1},
+	{file: "boat.rle", category: StillLife, period: 1},
+	{file: "tub.rle", category: StillLife, period: 1},
+	{file: "blinker.rle", category: Oscillator, period: 2},
+	{file: "toad.rle", category: Oscillator, period: 2},
+	{file: "beacon.rle", category: Oscillator, period: 2},
+	{file: "pulsar.rle", category: Oscillator, period: 3},
+	{file: "pentadecathlon.rle", category: Oscillator, period: 15},
+	{file: "glider.rle", category: Spaceship, period: 4, speed: "c/4 diagonal"},
+	{file: "lwss.rle", category: Spaceship, period: 4, speed: "c/2 orthogonal"},
+	{file: "mwss.rle", category: Spaceship, period: 4, speed: "c/2 orthogonal"},
+	{file: "hwss.rle", category: Spaceship, period: 4, speed: "c/2 orthogonal"},
+	{file: "gosperglidergun.rle", category: Gun, period: 30},
+	{file: "puffertrain.rle", category: Puffer, period: 128, speed: "c/2 orthogonal"},
+	{file: "rpentomino.rle", category: Methuselah},
+	{file: "diehard.rle", category: Methuselah},
+	{file: "acorn.rle", category: Methuselah},
+}
+
+// Entries returns the patterns of the library grouped by the category.
+func Entries() ([]Entry, error) {
+	entries := make([]Entry, 0, len(catalog))
+
+	for _, c := range catalog {
+		f, err := files.Open("patterns/" + c.file)
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", c.file, err)
+		}
+
+		p, err := pattern.ReadRLE(f)
+		_ = f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", c.file, err)
+		}
+
+		entries = append(entries, Entry{Pattern: p, Category: c.category, Period: c.period, Speed: c.speed})
+	}
+
+	return entries, nil
+}
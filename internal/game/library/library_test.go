@@ -0,0 +1,73 @@
+package library_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/library"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/period"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+func TestEntries(t *testing.T) {
+	entries, err := library.Entries()
+	require.NoError(t, err)
+
+	categories := map[library.Category]int{}
+
+	for _, e := range entries {
+		t.Run(e.Pattern.Name, func(t *testing.T) {
+			assert.NotEmpty(t, e.Pattern.Name)
+			assert.NotEmpty(t, e.Pattern.Author)
+			assert.NotEmpty(t, e.Pattern.Cells)
+
+			u := sparse.New()
+			e.Pattern.Place(u, 0, 0)
+
+			switch e.Category {
+			case library.StillLife, library.Oscillator, library.Spaceship:
+				// The pattern repeats itself after the period and not earlier.
+				// The spaceships are compared without their position.
+				for generation := 1; generation < e.Period; generation++ {
+					u.NextGeneration()
+					assert.NotEqual(t, e.Pattern.Cells, pattern.FromEngine(u).Cells, "generation %d", generation)
+				}
+
+				u.NextGeneration()
+				assert.Equal(t, e.Pattern.Cells, pattern.FromEngine(u).Cells)
+				assert.Equal(t, e.Category == library.Spaceship, u.BoundingBox().MinX != 0 || u.BoundingBox().MinY != 0)
+
+				// The speed is the one the spaceship is found to move at.
+				u = sparse.New()
+				e.Pattern.Place(u, 0, 0)
+				result, ok := period.Find(u, e.Period)
+				require.True(t, ok)
+				assert.Equal(t, e.Speed, result.Speed())
+			case library.Gun:
+				// Every period the gun emits a glider of five cells.
+				engine.Advance(u, 10*e.Period)
+				population := len(engine.AliveCells(u))
+				engine.Advance(u, e.Period)
+				assert.Equal(t, population+5, len(engine.AliveCells(u)))
+			case library.Puffer:
+				// The front of the puffer moves at c/2.
+				engine.Advance(u, e.Period)
+				front := u.BoundingBox().MaxX
+				engine.Advance(u, e.Period)
+				assert.Equal(t, front+e.Period/2, u.BoundingBox().MaxX)
+			case library.Methuselah:
+				assert.Zero(t, e.Period)
+			}
+		})
+
+		categories[e.Category]++
+	}
+
+	for c := library.StillLife; c <= library.Methuselah; c++ {
+		assert.Positive(t, categories[c], c.String())
+	}
+}
@@ -13,3 +13,44 @@ func IsLeftButtonPressed(msg tea.MouseMsg) bool {
 func IsClickWithinArea(msg tea.MouseMsg, xMin, yMin, xMax, yMax int) bool {
 	return msg.X >= xMin && msg.X <= xMax && msg.Y >= yMin && msg.Y <= yMax
 }
+
+// IsMiddleButtonPressed checks if the middle mouse button is pressed.
+func IsMiddleButtonPressed(msg tea.MouseMsg) bool {
+	event := tea.MouseEvent(msg)
+
+	return event.Button == tea.MouseButtonMiddle && event.Action == tea.MouseActionPress
+}
+
+// IsRightButtonPressed checks if the right mouse button is pressed.
+func IsRightButtonPressed(msg tea.MouseMsg) bool {
+	event := tea.MouseEvent(msg)
+
+	return event.Button == tea.MouseButtonRight && event.Action == tea.MouseActionPress
+}
+
+// IsMotion checks if the mouse is moved.
+func IsMotion(msg tea.MouseMsg) bool {
+	return tea.MouseEvent(msg).Action == tea.MouseActionMotion
+}
+
+// IsDragged checks if the mouse is moved with a button held down.
+func IsDragged(msg tea.MouseMsg) bool {
+	event := tea.MouseEvent(msg)
+
+	return event.Action == tea.MouseActionMotion && event.Button != tea.MouseButtonNone
+}
+
+// IsReleased checks if a mouse button is released.
+func IsReleased(msg tea.MouseMsg) bool {
+	return tea.MouseEvent(msg).Action == tea.MouseActionRelease
+}
+
+// IsWheelUp checks if the mouse wheel is scrolled up.
+func IsWheelUp(msg tea.MouseMsg) bool {
+	return tea.MouseEvent(msg).Button == tea.MouseButtonWheelUp
+}
+
+// IsWheelDown checks if the mouse wheel is scrolled down.
+func IsWheelDown(msg tea.MouseMsg) bool {
+	return tea.MouseEvent(msg).Button == tea.MouseButtonWheelDown
+}
@@ -94,3 +94,72 @@ func TestIsClickWithinArea(t *testing.T) {
 		})
 	}
 }
+
+func TestMouseEvents(t *testing.T) {
+	tt := []struct {
+		name          string
+		msg           tea.MouseMsg
+		middlePressed bool
+		rightPressed  bool
+		motion        bool
+		dragged       bool
+		released      bool
+		wheelUp       bool
+		wheelDown     bool
+	}{
+		{
+			name:          "middle mouse button is pressed",
+			msg:           tea.MouseMsg{Button: tea.MouseButtonMiddle, Action: tea.MouseActionPress},
+			middlePressed: true,
+		},
+		{
+			name:         "right mouse button is pressed",
+			msg:          tea.MouseMsg{Button: tea.MouseButtonRight, Action: tea.MouseActionPress},
+			rightPressed: true,
+		},
+		{
+			name:    "mouse is moved with the middle button",
+			msg:     tea.MouseMsg{Button: tea.MouseButtonMiddle, Action: tea.MouseActionMotion},
+			motion:  true,
+			dragged: true,
+		},
+		{
+			name:    "mouse is moved with the left button",
+			msg:     tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion},
+			motion:  true,
+			dragged: true,
+		},
+		{
+			name:   "mouse is moved without buttons",
+			msg:    tea.MouseMsg{Button: tea.MouseButtonNone, Action: tea.MouseActionMotion},
+			motion: true,
+		},
+		{
+			name:     "mouse button is released",
+			msg:      tea.MouseMsg{Button: tea.MouseButtonNone, Action: tea.MouseActionRelease},
+			released: true,
+		},
+		{
+			name:    "mouse wheel is scrolled up",
+			msg:     tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress},
+			wheelUp: true,
+		},
+		{
+			name:      "mouse wheel is scrolled down",
+			msg:       tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress},
+			wheelDown: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.middlePressed, mouse.IsMiddleButtonPressed(tc.msg))
+			assert.Equal(t, tc.rightPressed, mouse.IsRightButtonPressed(tc.msg))
+			assert.Equal(t, tc.motion, mouse.IsMotion(tc.msg))
+			assert.Equal(t, tc.dragged, mouse.IsDragged(tc.msg))
+			assert.Equal(t, tc.released, mouse.IsReleased(tc.msg))
+			assert.Equal(t, tc.wheelUp, mouse.IsWheelUp(tc.msg))
+			assert.Equal(t, tc.wheelDown, mouse.IsWheelDown(tc.msg))
+		})
+	}
+}
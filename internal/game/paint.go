@@ -0,0 +1,51 @@
+package game
+
+import (
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+// paint is the drawing of the cells by dragging the mouse.
+type paint struct {
+	// state is the state the cells are set to.
+	state *cell.Cell
+	// last is the cell the mouse cursor was over last time.
+	last engine.Point
+}
+
+// startPainting sets the cell to the state the cells are painted with. The
+// cells are erased if the cell is alive or erase is true, otherwise they are
+// drawn.
+func (g *Game) startPainting(p engine.Point, erase bool) {
+	// The painting that is not finished by releasing the button is recorded
+	// separately.
+	g.stopPainting()
+
+	state := cell.Alive
+	if erase || g.universe.Cell(p.X, p.Y) == cell.Alive {
+		state = cell.Dead
+	}
+
+	g.paint = &paint{state: state, last: p}
+	g.universe.SetCell(p.X, p.Y, state)
+}
+
+// continuePainting sets the cells on the line from the last cell to the given
+// one, so a fast drag does not leave gaps.
+func (g *Game) continuePainting(p engine.Point) {
+	for _, point := range engine.Line(g.paint.last, p) {
+		g.universe.SetCell(point.X, point.Y, g.paint.state)
+	}
+
+	g.paint.last = p
+}
+
+// stopPainting records the painted cells as a single change.
+func (g *Game) stopPainting() {
+	if g.paint == nil {
+		return
+	}
+
+	g.paint = nil
+	g.record()
+}
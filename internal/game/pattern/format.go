@@ -0,0 +1,147 @@
+package pattern
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a file format of the patterns.
+type Format int
+
+const (
+	// RLE is the Run Length Encoded format.
+	RLE Format = iota
+	// Plaintext is the plaintext format with '.' for dead and 'O' for alive cells.
+	Plaintext
+	// Life105 is the Life 1.05 format with the blocks of cells.
+	Life105
+	// Life106 is the Life 1.06 format with the coordinates of alive cells.
+	Life106
+	// MC is the Macrocell format with the deduplicated quadtree of cells.
+	MC
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case RLE:
+		return "RLE"
+	case Plaintext:
+		return "plaintext"
+	case Life105:
+		return "Life 1.05"
+	case Life106:
+		return "Life 1.06"
+	case MC:
+		return "Macrocell"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectFormat detects the format of the pattern by its content.
+func DetectFormat(data []byte) (Format, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#Life 1.05"):
+			return Life105, nil
+		case strings.HasPrefix(line, "#Life 1.06"):
+			return Life106, nil
+		case strings.HasPrefix(line, macrocellHeader):
+			return MC, nil
+		case strings.HasPrefix(line, "#"):
+			// The comments of the RLE format.
+			continue
+		case strings.HasPrefix(line, "!"):
+			// The comments of the plaintext format.
+			return Plaintext, nil
+		case strings.HasPrefix(line, "x") && strings.Contains(line, "="):
+			return RLE, nil
+		case strings.Trim(line, ".O*") == "":
+			return Plaintext, nil
+		default:
+			return 0, fmt.Errorf("unknown pattern format")
+		}
+	}
+
+	return 0, fmt.Errorf("unknown pattern format")
+}
+
+// FormatFromExtension returns the format of the pattern file by its
+// extension. Both Life 1.05 and Life 1.06 use the same extension, so the
+// newer Life 1.06 format is returned for it.
+func FormatFromExtension(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".rle":
+		return RLE, nil
+	case ".cells":
+		return Plaintext, nil
+	case ".lif", ".life":
+		return Life106, nil
+	case ".mc":
+		return MC, nil
+	default:
+		return 0, fmt.Errorf("unknown pattern file extension %q", filepath.Ext(path))
+	}
+}
+
+// Read reads the pattern in any supported format. The format is detected by
+// the content. The Macrocell patterns are expanded into the list of cells, so
+// ReadMacrocell should be used for huge patterns.
+func Read(r io.Reader) (*Pattern, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read pattern: %w", err)
+	}
+
+	format, err := DetectFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case Plaintext:
+		return ReadPlaintext(bytes.NewReader(data))
+	case Life105:
+		return ReadLife105(bytes.NewReader(data))
+	case Life106:
+		return ReadLife106(bytes.NewReader(data))
+	case MC:
+		m, err := ReadMacrocell(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		return m.Pattern(), nil
+	default:
+		return ReadRLE(bytes.NewReader(data))
+	}
+}
+
+// Write writes the pattern in the given format.
+func Write(w io.Writer, p *Pattern, format Format) error {
+	switch format {
+	case RLE:
+		return WriteRLE(w, p)
+	case Plaintext:
+		return WritePlaintext(w, p)
+	case Life105:
+		return WriteLife105(w, p)
+	case Life106:
+		return WriteLife106(w, p)
+	case MC:
+		return WriteMacrocell(w, MacrocellFromPattern(p))
+	default:
+		return fmt.Errorf("unknown pattern format %d", format)
+	}
+}
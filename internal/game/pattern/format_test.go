@@ -0,0 +1,119 @@
+package pattern_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tt := []struct {
+		name     string
+		data     string
+		expected pattern.Format
+	}{
+		{
+			name:     "RLE with comments",
+			data:     gliderRLE,
+			expected: pattern.RLE,
+		},
+		{
+			name:     "RLE without comments",
+			data:     "x = 3, y = 1\n3o!",
+			expected: pattern.RLE,
+		},
+		{
+			name:     "plaintext with comments",
+			data:     gliderPlaintext,
+			expected: pattern.Plaintext,
+		},
+		{
+			name:     "plaintext without comments",
+			data:     "\n.O.\n..O\nOOO\n",
+			expected: pattern.Plaintext,
+		},
+		{
+			name:     "Life 1.05",
+			data:     "#Life 1.05\n#N\n.*.\n",
+			expected: pattern.Life105,
+		},
+		{
+			name:     "Life 1.06",
+			data:     "#Life 1.06\n0 0\n",
+			expected: pattern.Life106,
+		},
+		{
+			name:     "Macrocell",
+			data:     gliderMacrocell,
+			expected: pattern.MC,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			format, err := pattern.DetectFormat([]byte(tc.data))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+}
+
+func TestDetectFormat_Unknown(t *testing.T) {
+	_, err := pattern.DetectFormat([]byte("hello world"))
+	assert.Error(t, err)
+
+	_, err = pattern.DetectFormat([]byte(""))
+	assert.Error(t, err)
+}
+
+func TestFormatFromExtension(t *testing.T) {
+	tt := []struct {
+		path     string
+		expected pattern.Format
+	}{
+		{path: "glider.rle", expected: pattern.RLE},
+		{path: "glider.RLE", expected: pattern.RLE},
+		{path: "glider.cells", expected: pattern.Plaintext},
+		{path: "glider.lif", expected: pattern.Life106},
+		{path: "glider.life", expected: pattern.Life106},
+		{path: "metapixel.mc", expected: pattern.MC},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.path, func(t *testing.T) {
+			format, err := pattern.FormatFromExtension(tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+
+	_, err := pattern.FormatFromExtension("glider.txt")
+	assert.Error(t, err)
+}
+
+func TestReadAndWrite(t *testing.T) {
+	p := &pattern.Pattern{
+		Rule:   rule.Conway,
+		Width:  3,
+		Height: 3,
+		Cells:  glider,
+	}
+
+	// All formats decode into the same pattern.
+	for _, format := range []pattern.Format{pattern.RLE, pattern.Plaintext, pattern.Life105, pattern.Life106, pattern.MC} {
+		t.Run(format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, pattern.Write(&buf, p, format))
+
+			actual, err := pattern.Read(strings.NewReader(buf.String()))
+			require.NoError(t, err)
+			assert.Equal(t, p, actual)
+		})
+	}
+}
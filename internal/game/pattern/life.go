@@ -0,0 +1,196 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+const (
+	life105Header = "#Life 1.05"
+	life106Header = "#Life 1.06"
+	// maxLife105LineLength is the maximum length of the rows in the Life 1.05 format.
+	maxLife105LineLength = 80
+)
+
+// ReadLife105 reads the pattern in the Life 1.05 format. The cells are
+// grouped into blocks, and each block starts with its position "#P x y".
+func ReadLife105(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Rule: rule.Conway}
+
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), life105Header) {
+		return nil, fmt.Errorf("read Life 1.05: missing header")
+	}
+
+	var blockX, blockY, row int
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#D"):
+			p.Comments = append(p.Comments, strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, "#N"):
+			p.Rule = rule.Conway
+		case strings.HasPrefix(line, "#R"):
+			r, err := rule.Parse(line[2:])
+			if err != nil {
+				return nil, fmt.Errorf("read Life 1.05: %w", err)
+			}
+
+			p.Rule = r
+		case strings.HasPrefix(line, "#P"):
+			x, y, err := parseCoordinates(line[2:])
+			if err != nil {
+				return nil, fmt.Errorf("read Life 1.05: invalid block position: %w", err)
+			}
+
+			blockX, blockY, row = x, y, 0
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			for x, ch := range line {
+				switch ch {
+				case '.':
+					continue
+				case '*':
+					p.Cells = append(p.Cells, engine.Point{X: blockX + x, Y: blockY + row})
+				default:
+					return nil, fmt.Errorf("read Life 1.05: unexpected character %q", ch)
+				}
+			}
+
+			row++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read Life 1.05: %w", err)
+	}
+
+	p.normalize()
+
+	return p, nil
+}
+
+// WriteLife105 writes the pattern in the Life 1.05 format. The name, the
+// author and the comments are written as descriptions. The pattern is
+// centered around the origin and split into blocks of 80 columns.
+func WriteLife105(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(life105Header + "\n")
+
+	if p.Name != "" {
+		fmt.Fprintf(bw, "#D %s\n", p.Name)
+	}
+
+	if p.Author != "" {
+		fmt.Fprintf(bw, "#D %s\n", p.Author)
+	}
+
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "#D %s\n", comment)
+	}
+
+	if p.Rule == rule.Conway {
+		bw.WriteString("#N\n")
+	} else {
+		fmt.Fprintf(bw, "#R %s\n", p.Rule.StringSB())
+	}
+
+	lines := rows(p, '.', '*')
+	for left := 0; left < p.Width; left += maxLife105LineLength {
+		right := min(left+maxLife105LineLength, p.Width)
+
+		fmt.Fprintf(bw, "#P %d %d\n", left-p.Width/2, -p.Height/2)
+
+		for _, line := range lines {
+			// The trailing dead cells can be omitted, but the row must not be empty.
+			block := strings.TrimRight(line[left:right], ".")
+			if block == "" {
+				block = "."
+			}
+
+			bw.WriteString(block)
+			bw.WriteString("\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadLife106 reads the pattern in the Life 1.06 format, which is a list of
+// the coordinates of alive cells.
+func ReadLife106(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Rule: rule.Conway}
+
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), life106Header) {
+		return nil, fmt.Errorf("read Life 1.06: missing header")
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		x, y, err := parseCoordinates(line)
+		if err != nil {
+			return nil, fmt.Errorf("read Life 1.06: %w", err)
+		}
+
+		p.Cells = append(p.Cells, engine.Point{X: x, Y: y})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read Life 1.06: %w", err)
+	}
+
+	p.normalize()
+
+	return p, nil
+}
+
+// WriteLife106 writes the pattern in the Life 1.06 format. The format has no
+// comments, so only the cells are written.
+func WriteLife106(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(life106Header + "\n")
+
+	for _, point := range p.Cells {
+		fmt.Fprintf(bw, "%d %d\n", point.X, point.Y)
+	}
+
+	return bw.Flush()
+}
+
+// parseCoordinates parses the coordinates like "-1 2".
+func parseCoordinates(s string) (int, int, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected two coordinates, got %q", s)
+	}
+
+	x, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid coordinate %q", fields[0])
+	}
+
+	y, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid coordinate %q", fields[1])
+	}
+
+	return x, y, nil
+}
@@ -0,0 +1,163 @@
+package pattern_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+func TestReadLife105(t *testing.T) {
+	tt := []struct {
+		name          string
+		life          string
+		expectedRule  rule.Rule
+		expectedCells []engine.Point
+	}{
+		{
+			name:          "single block with the normal rule",
+			life:          "#Life 1.05\n#D Glider\n#N\n#P -1 -1\n.*.\n..*\n***\n",
+			expectedRule:  rule.Conway,
+			expectedCells: glider,
+		},
+		{
+			name:          "several blocks with the rule in the S/B notation",
+			life:          "#Life 1.05\n#R 23/36\n#P 10 -5\n**\n#P -2 3\n.*\n",
+			expectedRule:  rule.HighLife,
+			expectedCells: []engine.Point{{X: 11, Y: 0}, {X: 12, Y: 0}, {X: 0, Y: 8}},
+		},
+		{
+			name:          "block without a position",
+			life:          "#Life 1.05\n*\n.*\n",
+			expectedRule:  rule.Conway,
+			expectedCells: []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 1}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := pattern.ReadLife105(strings.NewReader(tc.life))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRule, p.Rule)
+			assert.Equal(t, tc.expectedCells, p.Cells)
+		})
+	}
+}
+
+func TestReadLife105_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		life string
+	}{
+		{
+			name: "missing header",
+			life: "#P 0 0\n*\n",
+		},
+		{
+			name: "invalid rule",
+			life: "#Life 1.05\n#R 29/3\n*\n",
+		},
+		{
+			name: "invalid block position",
+			life: "#Life 1.05\n#P 0\n*\n",
+		},
+		{
+			name: "unexpected character",
+			life: "#Life 1.05\nO\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := pattern.ReadLife105(strings.NewReader(tc.life))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteLife105(t *testing.T) {
+	p := &pattern.Pattern{
+		Name:   "Glider",
+		Rule:   rule.HighLife,
+		Width:  3,
+		Height: 3,
+		Cells:  glider,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteLife105(&buf, p))
+	assert.Equal(t, "#Life 1.05\n#D Glider\n#R 23/36\n#P -1 -1\n.*\n..*\n***\n", buf.String())
+}
+
+func TestWriteLife105_RoundTrip(t *testing.T) {
+	// The pattern is wider than the maximum row length, so it is split into blocks.
+	p := &pattern.Pattern{
+		Comments: []string{"Two cells far away from each other."},
+		Rule:     rule.Conway,
+		Width:    200,
+		Height:   2,
+		Cells:    []engine.Point{{X: 0, Y: 0}, {X: 199, Y: 1}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteLife105(&buf, p))
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		assert.LessOrEqual(t, len(line), 80)
+	}
+
+	actual, err := pattern.ReadLife105(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, p, actual)
+}
+
+func TestReadLife106(t *testing.T) {
+	p, err := pattern.ReadLife106(strings.NewReader("#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, rule.Conway, p.Rule)
+	assert.Equal(t, 3, p.Width)
+	assert.Equal(t, 3, p.Height)
+	assert.Equal(t, glider, p.Cells)
+}
+
+func TestReadLife106_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		life string
+	}{
+		{
+			name: "missing header",
+			life: "0 0\n",
+		},
+		{
+			name: "missing coordinate",
+			life: "#Life 1.06\n0\n",
+		},
+		{
+			name: "invalid coordinate",
+			life: "#Life 1.06\n0 a\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := pattern.ReadLife106(strings.NewReader(tc.life))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteLife106(t *testing.T) {
+	p := &pattern.Pattern{Width: 3, Height: 3, Cells: glider}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteLife106(&buf, p))
+	assert.Equal(t, "#Life 1.06\n1 0\n2 1\n0 2\n1 2\n2 2\n", buf.String())
+}
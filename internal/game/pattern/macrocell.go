@@ -0,0 +1,540 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+const (
+	macrocellHeader = "[M2]"
+	// maxMacrocellLevel is the maximum level of the nodes, so the coordinates
+	// of the cells fit into int.
+	maxMacrocellLevel = 62
+)
+
+// Macrocell is a pattern in the Macrocell format of Golly. The cells are
+// stored in a deduplicated quadtree, so huge patterns take little space and
+// are loaded into the quadtree engines without expanding them into cells.
+type Macrocell struct {
+	// Rule is the rule the pattern is designed for.
+	Rule rule.Rule
+	// Generation is the generation the pattern is saved at.
+	Generation int
+	// Comments are the free-form comments about the pattern.
+	Comments []string
+	// nodes are the nodes of the quadtree in the order of the file, so the
+	// quadrants go before the nodes they belong to, and the root is the last
+	// node. The i-th node is referenced by i+1, and 0 references an empty node.
+	nodes []macrocellNode
+}
+
+// macrocellNode is a node of the quadtree in the Macrocell pattern.
+type macrocellNode struct {
+	level int
+	// children are the references to the nw, ne, sw and se quadrants of the
+	// nodes above the leaf level.
+	children [4]int
+	// rows are the cells of the leaf, the x-th bit of the y-th row is the cell
+	// in the x-th column and y-th row.
+	rows [8]uint8
+}
+
+// ReadMacrocell reads the pattern in the Macrocell format.
+func ReadMacrocell(r io.Reader) (*Macrocell, error) {
+	m := &Macrocell{Rule: rule.Conway}
+
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), macrocellHeader) {
+		return nil, fmt.Errorf("read Macrocell: missing header")
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#R"):
+			r, err := rule.Parse(line[2:])
+			if err != nil {
+				return nil, fmt.Errorf("read Macrocell: %w", err)
+			}
+
+			m.Rule = r
+		case strings.HasPrefix(line, "#G"):
+			generation, err := strconv.Atoi(strings.TrimSpace(line[2:]))
+			if err != nil {
+				return nil, fmt.Errorf("read Macrocell: invalid generation %q", line[2:])
+			}
+
+			m.Generation = generation
+		case strings.HasPrefix(line, "#C"), strings.HasPrefix(line, "#D"):
+			m.Comments = append(m.Comments, strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, "#"):
+			continue
+		case line[0] >= '0' && line[0] <= '9':
+			n, err := m.parseNode(line)
+			if err != nil {
+				return nil, fmt.Errorf("read Macrocell: node %d: %w", len(m.nodes)+1, err)
+			}
+
+			m.nodes = append(m.nodes, n)
+		default:
+			n, err := parseMacrocellLeaf(line)
+			if err != nil {
+				return nil, fmt.Errorf("read Macrocell: node %d: %w", len(m.nodes)+1, err)
+			}
+
+			m.nodes = append(m.nodes, n)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read Macrocell: %w", err)
+	}
+
+	return m, nil
+}
+
+// WriteMacrocell writes the pattern in the Macrocell format.
+func WriteMacrocell(w io.Writer, m *Macrocell) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(macrocellHeader + "\n")
+	fmt.Fprintf(bw, "#R %s\n", m.Rule)
+
+	if m.Generation != 0 {
+		fmt.Fprintf(bw, "#G %d\n", m.Generation)
+	}
+
+	for _, comment := range m.Comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+
+	for _, n := range m.nodes {
+		if n.level > engine.LeafLevel {
+			fmt.Fprintf(bw, "%d %d %d %d %d\n", n.level, n.children[0], n.children[1], n.children[2], n.children[3])
+			continue
+		}
+
+		var sb strings.Builder
+		for _, row := range n.rows {
+			for x := 0; row>>x != 0; x++ {
+				if row&(1<<x) != 0 {
+					sb.WriteByte('*')
+				} else {
+					sb.WriteByte('.')
+				}
+			}
+
+			sb.WriteByte('$')
+		}
+
+		// The trailing empty rows can be omitted.
+		bw.WriteString(strings.TrimRight(sb.String(), "$"))
+		bw.WriteString("$\n")
+	}
+
+	return bw.Flush()
+}
+
+// LoadMacrocell reads the pattern in the Macrocell format from the file.
+func LoadMacrocell(path string) (*Macrocell, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("open pattern file: %w", err)
+	}
+	defer f.Close()
+
+	m, err := ReadMacrocell(f)
+	if err != nil {
+		return nil, fmt.Errorf("read pattern file %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// SaveMacrocell writes the pattern in the Macrocell format to the file.
+func SaveMacrocell(path string, m *Macrocell) error {
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create pattern file: %w", err)
+	}
+
+	if err := WriteMacrocell(f, m); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write pattern file %s: %w", path, err)
+	}
+
+	return f.Close()
+}
+
+// MacrocellFromEngine creates a pattern from the alive cells of the engine.
+// The cells keep their coordinates. The nodes of the quadtree engines are
+// taken as they are, and the cells of the other engines are split into a new
+// quadtree.
+func MacrocellFromEngine(e engine.Engine) *Macrocell {
+	m := &Macrocell{Rule: e.Rule(), Generation: e.Generation()}
+
+	if q, ok := e.(engine.Quadtree); ok {
+		m.addNode(q, q.Root(), make(map[engine.Node]int))
+		return m
+	}
+
+	m.addCells(engine.AliveCells(e))
+
+	return m
+}
+
+// MacrocellFromPattern creates a pattern in the Macrocell format from the
+// pattern. The pattern is centered around the origin, and the name and the
+// author are kept as comments.
+func MacrocellFromPattern(p *Pattern) *Macrocell {
+	m := &Macrocell{Rule: p.Rule}
+
+	if p.Name != "" {
+		m.Comments = append(m.Comments, p.Name)
+	}
+
+	if p.Author != "" {
+		m.Comments = append(m.Comments, p.Author)
+	}
+
+	m.Comments = append(m.Comments, p.Comments...)
+
+	cells := make([]engine.Point, len(p.Cells))
+	for i, point := range p.Cells {
+		cells[i] = engine.Point{X: point.X - p.Width/2, Y: point.Y - p.Height/2}
+	}
+
+	m.addCells(cells)
+
+	return m
+}
+
+// Pattern expands the quadtree into the list of alive cells. It is meant for
+// the patterns small enough to be placed onto a bounded grid.
+func (m *Macrocell) Pattern() *Pattern {
+	p := &Pattern{Rule: m.Rule, Comments: m.Comments}
+
+	ref, x, y := m.root()
+	m.visit(ref, x, y, func(x, y int) {
+		p.Cells = append(p.Cells, engine.Point{X: x, Y: y})
+	})
+
+	slices.SortFunc(p.Cells, func(a, b engine.Point) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+
+		return a.X - b.X
+	})
+
+	p.normalize()
+
+	return p
+}
+
+// BoundingBox returns the smallest rectangle that contains all alive cells.
+func (m *Macrocell) BoundingBox() engine.Rect {
+	ref, x, y := m.root()
+
+	bounds := m.bounds(ref, make(map[int]engine.Rect))
+	if bounds.Empty() {
+		return bounds
+	}
+
+	return engine.Rect{MinX: bounds.MinX + x, MinY: bounds.MinY + y, MaxX: bounds.MaxX + x, MaxY: bounds.MaxY + y}
+}
+
+// Place sets the alive cells of the pattern onto the engine keeping their
+// coordinates. The quadtree engine gets the whole quadtree and the generation
+// of the pattern at once, so its previous cells are replaced. The other
+// engines get the cells one by one.
+func (m *Macrocell) Place(e engine.Engine) {
+	q, ok := e.(engine.Quadtree)
+	if !ok {
+		ref, x, y := m.root()
+		m.visit(ref, x, y, func(x, y int) {
+			e.SetCell(x, y, cell.Alive)
+		})
+
+		return
+	}
+
+	if len(m.nodes) == 0 {
+		q.SetRoot(q.EmptyNode(engine.LeafLevel), m.Generation)
+		return
+	}
+
+	// The quadrants always go before the nodes they belong to.
+	nodes := make([]engine.Node, len(m.nodes))
+	for i, n := range m.nodes {
+		if n.level == engine.LeafLevel {
+			nodes[i] = q.Leaf(n.rows)
+			continue
+		}
+
+		var children [4]engine.Node
+		for j, ref := range n.children {
+			if ref == 0 {
+				children[j] = q.EmptyNode(n.level - 1)
+			} else {
+				children[j] = nodes[ref-1]
+			}
+		}
+
+		nodes[i] = q.Join(children[0], children[1], children[2], children[3])
+	}
+
+	q.SetRoot(nodes[len(nodes)-1], m.Generation)
+}
+
+// parseNode parses the node above the leaf level like "4 1 0 2 3", where the
+// first number is the level and the others reference the quadrants.
+func (m *Macrocell) parseNode(line string) (macrocellNode, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 5 {
+		return macrocellNode{}, fmt.Errorf("expected level and four quadrants, got %q", line)
+	}
+
+	numbers := make([]int, len(fields))
+	for i, field := range fields {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 0 {
+			return macrocellNode{}, fmt.Errorf("invalid number %q", field)
+		}
+
+		numbers[i] = number
+	}
+
+	n := macrocellNode{level: numbers[0]}
+	if n.level <= engine.LeafLevel || n.level > maxMacrocellLevel {
+		return macrocellNode{}, fmt.Errorf("invalid level %d", n.level)
+	}
+
+	for i, ref := range numbers[1:] {
+		if ref > len(m.nodes) {
+			return macrocellNode{}, fmt.Errorf("reference to the unknown node %d", ref)
+		}
+
+		if ref != 0 && m.nodes[ref-1].level != n.level-1 {
+			return macrocellNode{}, fmt.Errorf("quadrant %d has level %d, expected %d", ref, m.nodes[ref-1].level, n.level-1)
+		}
+
+		n.children[i] = ref
+	}
+
+	return n, nil
+}
+
+// parseMacrocellLeaf parses the leaf of 8x8 cells like "..*$...*$.***$", where
+// '.' is a dead cell, '*' is an alive cell and '$' ends the row.
+func parseMacrocellLeaf(line string) (macrocellNode, error) {
+	n := macrocellNode{level: engine.LeafLevel}
+
+	var x, y int
+
+	for _, ch := range line {
+		switch ch {
+		case '.', '*':
+			if x >= 8 || y >= 8 {
+				return macrocellNode{}, fmt.Errorf("leaf %q is larger than 8x8", line)
+			}
+
+			if ch == '*' {
+				n.rows[y] |= 1 << x
+			}
+
+			x++
+		case '$':
+			x = 0
+			y++
+		default:
+			return macrocellNode{}, fmt.Errorf("unexpected character %q", ch)
+		}
+	}
+
+	return n, nil
+}
+
+// root returns the reference to the root and the coordinates of its top left
+// corner. The root is centered around the origin.
+func (m *Macrocell) root() (int, int, int) {
+	if len(m.nodes) == 0 {
+		return 0, 0, 0
+	}
+
+	half := 1 << (m.nodes[len(m.nodes)-1].level - 1)
+
+	return len(m.nodes), -half, -half
+}
+
+// visit calls the function for every alive cell of the referenced node with
+// the top left corner in the x-th column and y-th row.
+func (m *Macrocell) visit(ref, x, y int, fn func(x, y int)) {
+	if ref == 0 {
+		return
+	}
+
+	n := m.nodes[ref-1]
+	if n.level == engine.LeafLevel {
+		for dy, row := range n.rows {
+			for dx := range 8 {
+				if row&(1<<dx) != 0 {
+					fn(x+dx, y+dy)
+				}
+			}
+		}
+
+		return
+	}
+
+	half := 1 << (n.level - 1)
+	for i, child := range n.children {
+		m.visit(child, x+(i%2)*half, y+(i/2)*half, fn)
+	}
+}
+
+// bounds returns the smallest rectangle that contains all alive cells of the
+// referenced node relative to its top left corner.
+func (m *Macrocell) bounds(ref int, memo map[int]engine.Rect) engine.Rect {
+	if ref == 0 {
+		return engine.Rect{}
+	}
+
+	if bounds, ok := memo[ref]; ok {
+		return bounds
+	}
+
+	var bounds engine.Rect
+
+	n := m.nodes[ref-1]
+	if n.level == engine.LeafLevel {
+		m.visit(ref, 0, 0, func(x, y int) {
+			bounds = bounds.Union(engine.Rect{MinX: x, MinY: y, MaxX: x + 1, MaxY: y + 1})
+		})
+	} else {
+		half := 1 << (n.level - 1)
+		for i, child := range n.children {
+			b := m.bounds(child, memo)
+			if b.Empty() {
+				continue
+			}
+
+			dx, dy := (i%2)*half, (i/2)*half
+			bounds = bounds.Union(engine.Rect{MinX: b.MinX + dx, MinY: b.MinY + dy, MaxX: b.MaxX + dx, MaxY: b.MaxY + dy})
+		}
+	}
+
+	memo[ref] = bounds
+
+	return bounds
+}
+
+// addNode adds the node of the quadtree engine and its quadrants, and returns
+// the reference to it. The equal nodes are the same in the canonical
+// quadtree, so they are added once.
+func (m *Macrocell) addNode(q engine.Quadtree, n engine.Node, refs map[engine.Node]int) int {
+	if n.Population() == 0 {
+		return 0
+	}
+
+	if ref, ok := refs[n]; ok {
+		return ref
+	}
+
+	mn := macrocellNode{level: n.Level()}
+	if mn.level == engine.LeafLevel {
+		mn.rows = q.Rows(n)
+	} else {
+		nw, ne, sw, se := q.Quadrants(n)
+		for i, child := range []engine.Node{nw, ne, sw, se} {
+			mn.children[i] = m.addNode(q, child, refs)
+		}
+	}
+
+	m.nodes = append(m.nodes, mn)
+	refs[n] = len(m.nodes)
+
+	return len(m.nodes)
+}
+
+// addCells builds the quadtree of the cells. The root is the smallest node
+// centered around the origin that contains all cells.
+func (m *Macrocell) addCells(cells []engine.Point) {
+	if len(cells) == 0 {
+		return
+	}
+
+	level := engine.LeafLevel
+	for _, point := range cells {
+		for {
+			half := 1 << (level - 1)
+			if point.X >= -half && point.X < half && point.Y >= -half && point.Y < half {
+				break
+			}
+
+			level++
+		}
+	}
+
+	half := 1 << (level - 1)
+	m.addQuadrant(cells, level, -half, -half, make(map[macrocellNode]int))
+}
+
+// addQuadrant adds the node of the given level with the top left corner in
+// the x-th column and y-th row, and returns the reference to it. The cells
+// are inside the node, and the equal nodes are added once.
+func (m *Macrocell) addQuadrant(cells []engine.Point, level, x, y int, refs map[macrocellNode]int) int {
+	if len(cells) == 0 {
+		return 0
+	}
+
+	n := macrocellNode{level: level}
+
+	if level == engine.LeafLevel {
+		for _, point := range cells {
+			n.rows[point.Y-y] |= 1 << (point.X - x)
+		}
+	} else {
+		half := 1 << (level - 1)
+
+		var quadrants [4][]engine.Point
+		for _, point := range cells {
+			i := 0
+			if point.X >= x+half {
+				i++
+			}
+
+			if point.Y >= y+half {
+				i += 2
+			}
+
+			quadrants[i] = append(quadrants[i], point)
+		}
+
+		for i, quadrant := range quadrants {
+			n.children[i] = m.addQuadrant(quadrant, level-1, x+(i%2)*half, y+(i/2)*half, refs)
+		}
+	}
+
+	if ref, ok := refs[n]; ok {
+		return ref
+	}
+
+	m.nodes = append(m.nodes, n)
+	refs[n] = len(m.nodes)
+
+	return len(m.nodes)
+}
@@ -0,0 +1,196 @@
+package pattern_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/hashlife"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+// gliderMacrocell is the glider centered around the origin in a single leaf.
+const gliderMacrocell = `[M2]
+#R B3/S23
+$$$....*$.....*$...***$
+`
+
+func TestReadMacrocell(t *testing.T) {
+	m, err := pattern.ReadMacrocell(strings.NewReader("[M2] (golly 4.2)\n#R B36/S23\n#G 100\n#C Glider\n$$$$$$$*$\n$.......*$\n.*$**$\n4 0 1 2 3\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, rule.HighLife, m.Rule)
+	assert.Equal(t, 100, m.Generation)
+	assert.Equal(t, []string{"Glider"}, m.Comments)
+	assert.Equal(t, engine.Rect{MinX: -1, MinY: -1, MaxX: 2, MaxY: 2}, m.BoundingBox())
+
+	p := m.Pattern()
+	assert.Equal(t, 3, p.Width)
+	assert.Equal(t, 3, p.Height)
+	assert.Equal(t, glider, p.Cells)
+}
+
+func TestReadMacrocell_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		mc   string
+	}{
+		{
+			name: "missing header",
+			mc:   ".*$\n",
+		},
+		{
+			name: "invalid rule",
+			mc:   "[M2]\n#R B9/S23\n",
+		},
+		{
+			name: "invalid generation",
+			mc:   "[M2]\n#G many\n",
+		},
+		{
+			name: "leaf wider than 8 cells",
+			mc:   "[M2]\n.........*$\n",
+		},
+		{
+			name: "leaf higher than 8 cells",
+			mc:   "[M2]\n$$$$$$$$*$\n",
+		},
+		{
+			name: "unexpected character in leaf",
+			mc:   "[M2]\n.o$\n",
+		},
+		{
+			name: "missing quadrant",
+			mc:   "[M2]\n*$\n4 1 0 0\n",
+		},
+		{
+			name: "reference to unknown node",
+			mc:   "[M2]\n*$\n4 1 0 0 2\n",
+		},
+		{
+			name: "quadrant of wrong level",
+			mc:   "[M2]\n*$\n4 1 0 0 0\n5 0 0 0 1\n6 0 0 0 1\n",
+		},
+		{
+			name: "leaf level written as node",
+			mc:   "[M2]\n3 0 0 0 0\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := pattern.ReadMacrocell(strings.NewReader(tc.mc))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteMacrocell(t *testing.T) {
+	p := &pattern.Pattern{Rule: rule.Conway, Width: 3, Height: 3, Cells: glider}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteMacrocell(&buf, pattern.MacrocellFromPattern(p)))
+	assert.Equal(t, gliderMacrocell, buf.String())
+}
+
+func TestWriteMacrocell_DeduplicatesNodes(t *testing.T) {
+	// Four blocks in the corners of a large square.
+	u := sparse.New()
+	for _, corner := range []engine.Point{{X: -1000, Y: -1000}, {X: 998, Y: -1000}, {X: -1000, Y: 998}, {X: 998, Y: 998}} {
+		addBlock(u, corner.X, corner.Y)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteMacrocell(&buf, pattern.MacrocellFromEngine(u)))
+
+	// Every block is on the border of its leaf, so there are four different
+	// leaves, and every node above them is written once.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2+4+4*7+1)
+
+	m, err := pattern.ReadMacrocell(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, engine.Rect{MinX: -1000, MinY: -1000, MaxX: 1000, MaxY: 1000}, m.BoundingBox())
+}
+
+func TestMacrocell_Place(t *testing.T) {
+	m, err := pattern.ReadMacrocell(strings.NewReader(gliderMacrocell))
+	require.NoError(t, err)
+
+	expected := []engine.Point{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+
+	// The quadtree engine gets the nodes, and the other engines get the cells.
+	for _, e := range []engine.Engine{hashlife.New(), sparse.New()} {
+		m.Place(e)
+		assert.Equal(t, expected, engine.AliveCells(e))
+	}
+}
+
+func TestMacrocell_QuadtreeRoundTrip(t *testing.T) {
+	u := hashlife.New(hashlife.WithRule(rule.HighLife))
+	pattern.MacrocellFromPattern(&pattern.Pattern{Width: 3, Height: 3, Cells: glider}).Place(u)
+	addBlock(u, -5, -5)
+
+	// The glider flies far away from the block, so the quadtree is huge.
+	u.Advance(1 << 20)
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteMacrocell(&buf, pattern.MacrocellFromEngine(u)))
+
+	m, err := pattern.ReadMacrocell(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, rule.HighLife, m.Rule)
+	assert.Equal(t, 1<<20, m.Generation)
+
+	actual := hashlife.New(hashlife.WithRule(m.Rule))
+	m.Place(actual)
+	assert.Equal(t, u.Generation(), actual.Generation())
+	assert.Equal(t, u.Population(), actual.Population())
+	assert.Equal(t, u.BoundingBox(), actual.BoundingBox())
+	assert.Equal(t, u.BoundingBox(), m.BoundingBox())
+
+	u.Advance(100)
+	actual.Advance(100)
+	assert.Equal(t, u.BoundingBox(), actual.BoundingBox())
+}
+
+func TestMacrocellFromEngine_Grid(t *testing.T) {
+	g := grid.New(10, 10)
+	addBlock(g, 8, 8)
+
+	m := pattern.MacrocellFromEngine(g)
+	assert.Equal(t, engine.Rect{MinX: 8, MinY: 8, MaxX: 10, MaxY: 10}, m.BoundingBox())
+}
+
+func TestSaveAndLoadMacrocell(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glider.mc")
+	m := pattern.MacrocellFromPattern(&pattern.Pattern{Rule: rule.Conway, Width: 3, Height: 3, Cells: glider})
+	m.Generation = 7
+
+	require.NoError(t, pattern.SaveMacrocell(path, m))
+
+	actual, err := pattern.LoadMacrocell(path)
+	require.NoError(t, err)
+	assert.Equal(t, m, actual)
+
+	// The generic loader expands the quadtree into the cells.
+	p, err := pattern.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, glider, p.Cells)
+}
+
+func addBlock(e engine.Engine, x, y int) {
+	e.SetCell(x, y, cell.Alive)
+	e.SetCell(x+1, y, cell.Alive)
+	e.SetCell(x, y+1, cell.Alive)
+	e.SetCell(x+1, y+1, cell.Alive)
+}
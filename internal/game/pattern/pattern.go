@@ -0,0 +1,119 @@
+package pattern
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+const (
+	// MaxSize is the largest width and height of the patterns read from the
+	// files, the size of the largest grid.
+	MaxSize = 1 << 16
+	// MaxCells is the largest number of alive cells of the patterns read from
+	// the Run Length Encoded files.
+	MaxCells = 1 << 20
+)
+
+// Pattern represents a pattern of alive cells that can be loaded from a file
+// and placed onto an engine.
+type Pattern struct {
+	// Name is the name of the pattern.
+	Name string
+	// Author is the author of the pattern or the person who discovered it.
+	Author string
+	// Comments are the free-form comments about the pattern.
+	Comments []string
+	// Rule is the rule the pattern is designed for.
+	Rule rule.Rule
+	// Width is the number of columns in the pattern.
+	Width int
+	// Height is the number of rows in the pattern.
+	Height int
+	// Cells are the alive cells relative to the top left corner of the pattern.
+	Cells []engine.Point
+}
+
+// FromEngine creates a pattern from the alive cells of the engine. The
+// pattern is cropped to the bounding box of the alive cells.
+func FromEngine(e engine.Engine) *Pattern {
+	bounds := e.BoundingBox()
+
+	p := &Pattern{
+		Rule:   e.Rule(),
+		Width:  bounds.Width(),
+		Height: bounds.Height(),
+	}
+
+	for _, point := range engine.AliveCells(e) {
+		p.Cells = append(p.Cells, engine.Point{X: point.X - bounds.MinX, Y: point.Y - bounds.MinY})
+	}
+
+	return p
+}
+
+// Place sets the alive cells of the pattern onto the engine with the top left
+// corner of the pattern in the x-th column and y-th row.
+func (p *Pattern) Place(e engine.Engine, x, y int) {
+	for _, point := range p.Cells {
+		e.SetCell(x+point.X, y+point.Y, cell.Alive)
+	}
+}
+
+// Load reads the pattern from the file. The format is detected by the content.
+func Load(path string) (*Pattern, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("open pattern file: %w", err)
+	}
+	defer f.Close()
+
+	p, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("read pattern file %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// Save writes the pattern to the file. The format is chosen by the extension
+// of the file, and the RLE format is used for unknown extensions.
+func Save(path string, p *Pattern) error {
+	format, err := FormatFromExtension(path)
+	if err != nil {
+		format = RLE
+	}
+
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create pattern file: %w", err)
+	}
+
+	if err := Write(f, p, format); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write pattern file %s: %w", path, err)
+	}
+
+	return f.Close()
+}
+
+// normalize moves the cells so the top left corner of the pattern is in the
+// origin, and sets the size of the pattern to fit all cells.
+func (p *Pattern) normalize() {
+	var bounds engine.Rect
+	for _, point := range p.Cells {
+		bounds = bounds.Union(engine.Rect{MinX: point.X, MinY: point.Y, MaxX: point.X + 1, MaxY: point.Y + 1})
+	}
+
+	for i := range p.Cells {
+		p.Cells[i].X -= bounds.MinX
+		p.Cells[i].Y -= bounds.MinY
+	}
+
+	p.Width = bounds.Width()
+	p.Height = bounds.Height()
+}
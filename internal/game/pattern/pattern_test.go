@@ -0,0 +1,61 @@
+package pattern_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+func TestPattern_Place(t *testing.T) {
+	p := &pattern.Pattern{Width: 3, Height: 3, Cells: glider}
+
+	u := sparse.New()
+	p.Place(u, -10, 5)
+
+	expected := []engine.Point{{X: -9, Y: 5}, {X: -8, Y: 6}, {X: -10, Y: 7}, {X: -9, Y: 7}, {X: -8, Y: 7}}
+	assert.Equal(t, expected, engine.AliveCells(u))
+}
+
+func TestFromEngine(t *testing.T) {
+	sg := grid.New(10, 10, grid.WithRule(rule.HighLife))
+	for _, point := range glider {
+		sg.SetCell(point.X+4, point.Y+2, cell.Alive)
+	}
+
+	p := pattern.FromEngine(sg)
+	assert.Equal(t, rule.HighLife, p.Rule)
+	assert.Equal(t, 3, p.Width)
+	assert.Equal(t, 3, p.Height)
+	assert.Equal(t, glider, p.Cells)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glider.rle")
+	p := &pattern.Pattern{
+		Name:   "Glider",
+		Rule:   rule.Conway,
+		Width:  3,
+		Height: 3,
+		Cells:  glider,
+	}
+
+	require.NoError(t, pattern.Save(path, p))
+
+	actual, err := pattern.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, p, actual)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := pattern.Load(filepath.Join(t.TempDir(), "missing.rle"))
+	assert.Error(t, err)
+}
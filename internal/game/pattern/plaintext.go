@@ -0,0 +1,108 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+// ReadPlaintext reads the pattern in the plaintext format. The lines starting
+// with '!' are comments, and the other lines are the rows of cells, where '.'
+// is a dead cell and 'O' is an alive cell.
+func ReadPlaintext(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Rule: rule.Conway}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if comment, ok := strings.CutPrefix(line, "!"); ok {
+			parsePlaintextComment(p, comment)
+			continue
+		}
+
+		for x, ch := range line {
+			switch ch {
+			case '.':
+				continue
+			case 'O', '*':
+				p.Cells = append(p.Cells, engine.Point{X: x, Y: p.Height})
+			default:
+				return nil, fmt.Errorf("read plaintext: unexpected character %q", ch)
+			}
+		}
+
+		p.Width = max(p.Width, len(line))
+		p.Height++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read plaintext: %w", err)
+	}
+
+	return p, nil
+}
+
+// WritePlaintext writes the pattern in the plaintext format.
+func WritePlaintext(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+
+	if p.Name != "" {
+		fmt.Fprintf(bw, "!Name: %s\n", p.Name)
+	}
+
+	if p.Author != "" {
+		fmt.Fprintf(bw, "!Author: %s\n", p.Author)
+	}
+
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "!%s\n", comment)
+	}
+
+	for _, row := range rows(p, '.', 'O') {
+		bw.WriteString(row)
+		bw.WriteString("\n")
+	}
+
+	return bw.Flush()
+}
+
+// parsePlaintextComment parses the comment. The name and the author are
+// written as "Name: ..." and "Author: ...".
+func parsePlaintextComment(p *Pattern, comment string) {
+	if name, ok := strings.CutPrefix(comment, "Name:"); ok {
+		p.Name = strings.TrimSpace(name)
+		return
+	}
+
+	if author, ok := strings.CutPrefix(comment, "Author:"); ok {
+		p.Author = strings.TrimSpace(author)
+		return
+	}
+
+	p.Comments = append(p.Comments, strings.TrimSpace(comment))
+}
+
+// rows returns the rows of the pattern, where the dead and alive cells are
+// represented by the given characters.
+func rows(p *Pattern, dead, alive byte) []string {
+	grid := make([][]byte, p.Height)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(string(dead), p.Width))
+	}
+
+	for _, point := range p.Cells {
+		grid[point.Y][point.X] = alive
+	}
+
+	lines := make([]string, len(grid))
+	for y, row := range grid {
+		lines[y] = string(row)
+	}
+
+	return lines
+}
@@ -0,0 +1,64 @@
+package pattern_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+const gliderPlaintext = `!Name: Glider
+!Author: Richard K. Guy
+!The smallest, most common, and first discovered spaceship.
+.O.
+..O
+OOO
+`
+
+func TestReadPlaintext(t *testing.T) {
+	p, err := pattern.ReadPlaintext(strings.NewReader(gliderPlaintext))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Glider", p.Name)
+	assert.Equal(t, "Richard K. Guy", p.Author)
+	assert.Equal(t, []string{"The smallest, most common, and first discovered spaceship."}, p.Comments)
+	assert.Equal(t, rule.Conway, p.Rule)
+	assert.Equal(t, 3, p.Width)
+	assert.Equal(t, 3, p.Height)
+	assert.Equal(t, glider, p.Cells)
+}
+
+func TestReadPlaintext_RowsOfDifferentLength(t *testing.T) {
+	p, err := pattern.ReadPlaintext(strings.NewReader("*\n\n..*\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, p.Width)
+	assert.Equal(t, 3, p.Height)
+	assert.Len(t, p.Cells, 2)
+}
+
+func TestReadPlaintext_Invalid(t *testing.T) {
+	_, err := pattern.ReadPlaintext(strings.NewReader(".O.\n.X.\n"))
+	assert.Error(t, err)
+}
+
+func TestWritePlaintext(t *testing.T) {
+	p := &pattern.Pattern{
+		Name:     "Glider",
+		Author:   "Richard K. Guy",
+		Comments: []string{"The smallest, most common, and first discovered spaceship."},
+		Rule:     rule.Conway,
+		Width:    3,
+		Height:   3,
+		Cells:    glider,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WritePlaintext(&buf, p))
+	assert.Equal(t, gliderPlaintext, buf.String())
+}
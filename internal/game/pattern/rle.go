@@ -0,0 +1,283 @@
+package pattern
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+// maxRLELineLength is the maximum length of the lines with the encoded cells.
+const maxRLELineLength = 70
+
+// ReadRLE reads the pattern in the Run Length Encoded format.
+func ReadRLE(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Rule: rule.Conway}
+
+	scanner := bufio.NewScanner(r)
+	headerFound := false
+
+	var body strings.Builder
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case !headerFound && strings.HasPrefix(line, "#"):
+			parseRLEComment(p, line)
+		case !headerFound:
+			if err := parseRLEHeader(p, line); err != nil {
+				return nil, err
+			}
+
+			headerFound = true
+		default:
+			body.WriteString(line)
+		}
+
+		// Everything after the end of the pattern is ignored.
+		if headerFound && strings.Contains(line, "!") {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read RLE: %w", err)
+	}
+
+	if !headerFound {
+		return nil, fmt.Errorf("read RLE: missing header")
+	}
+
+	if err := parseRLEBody(p, body.String()); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// WriteRLE writes the pattern in the Run Length Encoded format.
+func WriteRLE(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+
+	if p.Name != "" {
+		fmt.Fprintf(bw, "#N %s\n", p.Name)
+	}
+
+	if p.Author != "" {
+		fmt.Fprintf(bw, "#O %s\n", p.Author)
+	}
+
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+
+	fmt.Fprintf(bw, "x = %d, y = %d, rule = %s\n", p.Width, p.Height, p.Rule)
+
+	lineLength := 0
+	for _, token := range rleTokens(p) {
+		if lineLength+len(token) > maxRLELineLength {
+			bw.WriteString("\n")
+			lineLength = 0
+		}
+
+		bw.WriteString(token)
+		lineLength += len(token)
+	}
+
+	bw.WriteString("\n")
+
+	return bw.Flush()
+}
+
+// parseRLEComment parses the comment line. The unknown comments are ignored.
+func parseRLEComment(p *Pattern, line string) {
+	if len(line) < 2 {
+		return
+	}
+
+	text := strings.TrimSpace(line[2:])
+
+	switch line[1] {
+	case 'N':
+		p.Name = text
+	case 'O':
+		p.Author = text
+	case 'C', 'c':
+		p.Comments = append(p.Comments, text)
+	}
+}
+
+// parseRLEHeader parses the header line like "x = 3, y = 3, rule = B3/S23".
+func parseRLEHeader(p *Pattern, line string) error {
+	// The rule goes last and can contain commas in the bounded grid
+	// specification (e.g. "B3/S23:T40,30"), so it is parsed separately.
+	size, rulestring, ruleFound := strings.Cut(line, "rule")
+	if ruleFound {
+		if err := parseRLERule(p, rulestring); err != nil {
+			return err
+		}
+	}
+
+	var widthFound, heightFound bool
+
+	for _, field := range strings.Split(size, ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			return fmt.Errorf("read RLE: invalid header field %q", field)
+		}
+
+		key = strings.TrimSpace(key)
+
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 {
+			return fmt.Errorf("read RLE: invalid header field %q", field)
+		}
+
+		if n > MaxSize {
+			return fmt.Errorf("read RLE: pattern size %d exceeds %d", n, MaxSize)
+		}
+
+		switch key {
+		case "x":
+			p.Width = n
+			widthFound = true
+		case "y":
+			p.Height = n
+			heightFound = true
+		}
+	}
+
+	if !widthFound || !heightFound {
+		return fmt.Errorf("read RLE: header must contain the pattern size")
+	}
+
+	return nil
+}
+
+// parseRLERule parses the rule like " = B3/S23" from the header.
+func parseRLERule(p *Pattern, s string) error {
+	value, found := strings.CutPrefix(strings.TrimSpace(s), "=")
+	if !found {
+		return fmt.Errorf("read RLE: invalid rule field %q", s)
+	}
+
+	// The bounded grid specification (e.g. "B3/S23:T40,30") is ignored.
+	rulestring, _, _ := strings.Cut(value, ":")
+
+	r, err := rule.Parse(rulestring)
+	if err != nil {
+		return fmt.Errorf("read RLE: %w", err)
+	}
+
+	p.Rule = r
+
+	return nil
+}
+
+// parseRLEBody parses the encoded cells like "bo$2bo$3o!".
+func parseRLEBody(p *Pattern, body string) error {
+	x, y, count := 0, 0, 0
+
+	for _, ch := range body {
+		if ch >= '0' && ch <= '9' {
+			count = count*10 + int(ch-'0')
+			if count > MaxSize {
+				return fmt.Errorf("read RLE: run length exceeds %d", MaxSize)
+			}
+
+			continue
+		}
+
+		length := max(count, 1)
+		count = 0
+
+		switch ch {
+		case 'b', '.':
+			x += length
+		case 'o':
+			if x+length > p.Width || y >= p.Height {
+				return fmt.Errorf("read RLE: cells are outside the pattern size %dx%d", p.Width, p.Height)
+			}
+
+			if len(p.Cells)+length > MaxCells {
+				return fmt.Errorf("read RLE: pattern has more than %d cells", MaxCells)
+			}
+
+			for i := range length {
+				p.Cells = append(p.Cells, engine.Point{X: x + i, Y: y})
+			}
+
+			x += length
+		case '$':
+			x = 0
+			y += length
+		case '!':
+			return nil
+		case ' ', '\t':
+			continue
+		default:
+			return fmt.Errorf("read RLE: unexpected character %q", ch)
+		}
+	}
+
+	return nil
+}
+
+// rleTokens encodes the cells of the pattern into the runs like "2o", "3b" or "$".
+func rleTokens(p *Pattern) []string {
+	cells := slices.Clone(p.Cells)
+	slices.SortFunc(cells, func(a, b engine.Point) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+	cells = slices.Compact(cells)
+
+	var tokens []string
+
+	x, y := 0, 0
+	for i := 0; i < len(cells); {
+		// The trailing dead cells of the rows are omitted, so we just move to
+		// the row of the next alive cell.
+		if cells[i].Y > y {
+			tokens = append(tokens, run(cells[i].Y-y, '$'))
+			x, y = 0, cells[i].Y
+		}
+
+		if cells[i].X > x {
+			tokens = append(tokens, run(cells[i].X-x, 'b'))
+		}
+
+		// Count the adjacent alive cells in the row.
+		length := 1
+		for i+length < len(cells) && cells[i+length].Y == y && cells[i+length].X == cells[i].X+length {
+			length++
+		}
+
+		tokens = append(tokens, run(length, 'o'))
+		x = cells[i].X + length
+		i += length
+	}
+
+	return append(tokens, "!")
+}
+
+// run returns the encoded run of the given length, the length 1 is omitted.
+func run(length int, tag byte) string {
+	if length == 1 {
+		return string(tag)
+	}
+
+	return strconv.Itoa(length) + string(tag)
+}
@@ -0,0 +1,195 @@
+package pattern_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+const gliderRLE = `#N Glider
+#O Richard K. Guy
+#C The smallest, most common, and first discovered spaceship.
+#C www.conwaylife.com/wiki/index.php?title=Glider
+x = 3, y = 3, rule = B3/S23
+bo$2bo$3o!
+`
+
+var glider = []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}
+
+func TestReadRLE(t *testing.T) {
+	p, err := pattern.ReadRLE(strings.NewReader(gliderRLE))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Glider", p.Name)
+	assert.Equal(t, "Richard K. Guy", p.Author)
+	assert.Equal(t, []string{
+		"The smallest, most common, and first discovered spaceship.",
+		"www.conwaylife.com/wiki/index.php?title=Glider",
+	}, p.Comments)
+	assert.Equal(t, rule.Conway, p.Rule)
+	assert.Equal(t, 3, p.Width)
+	assert.Equal(t, 3, p.Height)
+	assert.Equal(t, glider, p.Cells)
+}
+
+func TestReadRLE_Body(t *testing.T) {
+	tt := []struct {
+		name          string
+		rle           string
+		expectedRule  rule.Rule
+		expectedCells []engine.Point
+	}{
+		{
+			name:          "multi-digit run counts",
+			rle:           "x = 14, y = 1\n12b2o!",
+			expectedRule:  rule.Conway,
+			expectedCells: []engine.Point{{X: 12, Y: 0}, {X: 13, Y: 0}},
+		},
+		{
+			name:          "multiple empty rows",
+			rle:           "x = 1, y = 12\no11$o!",
+			expectedRule:  rule.Conway,
+			expectedCells: []engine.Point{{X: 0, Y: 0}, {X: 0, Y: 11}},
+		},
+		{
+			name:          "cells are split into several lines",
+			rle:           "x = 4, y = 2, rule = B36/S23\n2o\nbo$\n4o\n!",
+			expectedRule:  rule.HighLife,
+			expectedCells: []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 3, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}},
+		},
+		{
+			name:          "rule in the S/B notation with the bounded grid",
+			rle:           "x = 1, y = 1, rule = 23/36:T40,30\no!",
+			expectedRule:  rule.HighLife,
+			expectedCells: []engine.Point{{X: 0, Y: 0}},
+		},
+		{
+			name:          "text after the end of the pattern is ignored",
+			rle:           "x = 1, y = 1\no! some text\n#C and more text",
+			expectedRule:  rule.Conway,
+			expectedCells: []engine.Point{{X: 0, Y: 0}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := pattern.ReadRLE(strings.NewReader(tc.rle))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRule, p.Rule)
+			assert.Equal(t, tc.expectedCells, p.Cells)
+		})
+	}
+}
+
+func TestReadRLE_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		rle  string
+	}{
+		{
+			name: "missing header",
+			rle:  "#C just a comment\n",
+		},
+		{
+			name: "missing pattern size",
+			rle:  "x = 3, rule = B3/S23\n3o!",
+		},
+		{
+			name: "invalid pattern size",
+			rle:  "x = -3, y = 1\n3o!",
+		},
+		{
+			name: "invalid rule",
+			rle:  "x = 3, y = 1, rule = B9/S23\n3o!",
+		},
+		{
+			name: "cells outside the pattern size",
+			rle:  "x = 2, y = 1\n3o!",
+		},
+		{
+			name: "unexpected character",
+			rle:  "x = 3, y = 1\n3z!",
+		},
+		{
+			name: "pattern width exceeds the maximum size",
+			rle:  "x = 999999999, y = 1\no!",
+		},
+		{
+			name: "pattern height exceeds the maximum size",
+			rle:  "x = 1, y = 65537\no!",
+		},
+		{
+			name: "run length exceeds the maximum size",
+			rle:  "x = 3, y = 1\n2147483647o!",
+		},
+		{
+			name: "run length overflows",
+			rle:  "x = 3, y = 1\n99999999999999999999999b!",
+		},
+		{
+			name: "too many cells",
+			rle:  "x = 65536, y = 17\n" + strings.Repeat("65536o$", 17) + "!",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := pattern.ReadRLE(strings.NewReader(tc.rle))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteRLE(t *testing.T) {
+	p := &pattern.Pattern{
+		Name:   "Glider",
+		Author: "Richard K. Guy",
+		Comments: []string{
+			"The smallest, most common, and first discovered spaceship.",
+			"www.conwaylife.com/wiki/index.php?title=Glider",
+		},
+		Rule:   rule.Conway,
+		Width:  3,
+		Height: 3,
+		Cells:  glider,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteRLE(&buf, p))
+	assert.Equal(t, gliderRLE, buf.String())
+}
+
+func TestWriteRLE_RoundTrip(t *testing.T) {
+	// The row is long enough to be split into several lines.
+	var cells []engine.Point
+	for x := 0; x < 200; x += 3 {
+		cells = append(cells, engine.Point{X: x, Y: 0}, engine.Point{X: x + 1, Y: 0})
+	}
+
+	cells = append(cells, engine.Point{X: 5, Y: 20}, engine.Point{X: 199, Y: 21})
+
+	p := &pattern.Pattern{
+		Rule:   rule.DayAndNight,
+		Width:  200,
+		Height: 22,
+		Cells:  cells,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pattern.WriteRLE(&buf, p))
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		assert.LessOrEqual(t, len(line), 70)
+	}
+
+	actual, err := pattern.ReadRLE(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, p, actual)
+}
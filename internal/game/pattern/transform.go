@@ -0,0 +1,73 @@
+package pattern
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+// FromRect creates a pattern from the alive cells of the engine inside the
+// rectangle. Unlike FromEngine, the pattern has the size of the rectangle,
+// so the dead cells around the alive ones are kept.
+func FromRect(e engine.Engine, r engine.Rect) *Pattern {
+	p := &Pattern{
+		Rule:   e.Rule(),
+		Width:  r.Width(),
+		Height: r.Height(),
+	}
+
+	bounds := e.BoundingBox().Intersect(r)
+	for y := bounds.MinY; y < bounds.MaxY; y++ {
+		for x := bounds.MinX; x < bounds.MaxX; x++ {
+			if e.Cell(x, y) == cell.Alive {
+				p.Cells = append(p.Cells, engine.Point{X: x - r.MinX, Y: y - r.MinY})
+			}
+		}
+	}
+
+	return p
+}
+
+// Rotate returns the pattern rotated by 90 degrees clockwise.
+func (p *Pattern) Rotate() *Pattern {
+	rotated := p.transform(func(point engine.Point) engine.Point {
+		return engine.Point{X: p.Height - 1 - point.Y, Y: point.X}
+	})
+	rotated.Width, rotated.Height = p.Height, p.Width
+
+	return rotated
+}
+
+// FlipHorizontal returns the pattern mirrored from left to right.
+func (p *Pattern) FlipHorizontal() *Pattern {
+	return p.transform(func(point engine.Point) engine.Point {
+		return engine.Point{X: p.Width - 1 - point.X, Y: point.Y}
+	})
+}
+
+// FlipVertical returns the pattern mirrored from top to bottom.
+func (p *Pattern) FlipVertical() *Pattern {
+	return p.transform(func(point engine.Point) engine.Point {
+		return engine.Point{X: point.X, Y: p.Height - 1 - point.Y}
+	})
+}
+
+// transform returns a copy of the pattern with the cells moved by the
+// function. The cells are ordered by rows and then by columns.
+func (p *Pattern) transform(move func(engine.Point) engine.Point) *Pattern {
+	transformed := *p
+	transformed.Comments = slices.Clone(p.Comments)
+	transformed.Cells = make([]engine.Point, len(p.Cells))
+
+	for i, point := range p.Cells {
+		transformed.Cells[i] = move(point)
+	}
+
+	slices.SortFunc(transformed.Cells, func(a, b engine.Point) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+
+	return &transformed
+}
@@ -0,0 +1,75 @@
+package pattern_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+// corner is the pattern:
+//
+//	o..
+//	ooo
+var corner = &pattern.Pattern{
+	Width:  3,
+	Height: 2,
+	Cells:  []engine.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+}
+
+func TestPattern_Transform(t *testing.T) {
+	tt := []struct {
+		name           string
+		transform      func(*pattern.Pattern) *pattern.Pattern
+		expectedWidth  int
+		expectedHeight int
+		expectedCells  []engine.Point
+	}{
+		{
+			name:           "rotate",
+			transform:      (*pattern.Pattern).Rotate,
+			expectedWidth:  2,
+			expectedHeight: 3,
+			expectedCells:  []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}},
+		},
+		{
+			name:           "flip horizontally",
+			transform:      (*pattern.Pattern).FlipHorizontal,
+			expectedWidth:  3,
+			expectedHeight: 2,
+			expectedCells:  []engine.Point{{X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+		},
+		{
+			name:           "flip vertically",
+			transform:      (*pattern.Pattern).FlipVertical,
+			expectedWidth:  3,
+			expectedHeight: 2,
+			expectedCells:  []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.transform(corner)
+			assert.Equal(t, tc.expectedWidth, p.Width)
+			assert.Equal(t, tc.expectedHeight, p.Height)
+			assert.Equal(t, tc.expectedCells, p.Cells)
+		})
+	}
+
+	// The original pattern is not changed, and four rotations give it back.
+	assert.Equal(t, corner, corner.Rotate().Rotate().Rotate().Rotate())
+}
+
+func TestFromRect(t *testing.T) {
+	u := sparse.New()
+	(&pattern.Pattern{Width: 3, Height: 3, Cells: glider}).Place(u, 5, 5)
+
+	p := pattern.FromRect(u, engine.Rect{MinX: 4, MinY: 4, MaxX: 9, MaxY: 7})
+	assert.Equal(t, 5, p.Width)
+	assert.Equal(t, 3, p.Height)
+	assert.Equal(t, []engine.Point{{X: 2, Y: 1}, {X: 3, Y: 2}}, p.Cells)
+}
@@ -0,0 +1,12 @@
+package period
+
+import "github.com/ivanlemeshev/gameoflife/internal/game/engine"
+
+// NewDetectorWithHash creates a detector that hashes the cells with the given
+// function, so the collisions of the hashes can be tested.
+func NewDetectorWithHash(maxPeriod int, hash func(points []engine.Point) uint64) *Detector {
+	d := NewDetector(maxPeriod)
+	d.hashPoints = hash
+
+	return d
+}
@@ -0,0 +1,383 @@
+// Package period detects when the cells become static, periodic or a moving
+// pattern by hashing every generation.
+package period
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+)
+
+const (
+	// DefaultMaxCells is the number of the alive cells the detector keeps of
+	// all remembered generations by default.
+	DefaultMaxCells = 1 << 20
+	// maxOffsets is the largest number of the offsets of a torus compared in
+	// each direction when the cells can be placed at several equal positions.
+	maxOffsets = 8
+)
+
+// Result describes how the cells repeat themselves.
+type Result struct {
+	// Generation is the first generation of the cycle.
+	Generation int
+	// Period is the number of generations after which the cells repeat.
+	Period int
+	// DX and DY are the number of columns and rows the cells move by every
+	// period.
+	DX int
+	DY int
+}
+
+// Static returns true if the cells do not change.
+func (r Result) Static() bool {
+	return r.Period == 1 && !r.Moving()
+}
+
+// Moving returns true if the cells move, like a spaceship.
+func (r Result) Moving() bool {
+	return r.DX != 0 || r.DY != 0
+}
+
+// Speed returns the speed of the moving cells in the usual notation, like
+// "c/4 diagonal", "2c/5 orthogonal" or "(2,1)c/6 oblique", where c is one cell
+// per generation. It returns an empty string if the cells do not move.
+func (r Result) Speed() string {
+	dx, dy := abs(r.DX), abs(r.DY)
+	a, b := max(dx, dy), min(dx, dy)
+
+	switch {
+	case a == 0:
+		return ""
+	case b == 0:
+		return fraction(a, r.Period) + " orthogonal"
+	case a == b:
+		return fraction(a, r.Period) + " diagonal"
+	default:
+		d := gcd(gcd(a, b), r.Period)
+		return fmt.Sprintf("(%d,%d)c/%d oblique", a/d, b/d, r.Period/d)
+	}
+}
+
+// Find moves the engine forward one generation at a time until its cells
+// repeat themselves, for example until a spaceship reappears at another
+// position, but for no more than the given number of generations. The engine
+// is changed, so a copy should be passed if its cells are still needed. It
+// returns false if the cells do not repeat themselves in time.
+func Find(e engine.Engine, generations int) (Result, bool) {
+	d := NewDetector(generations)
+
+	for range generations {
+		if result, ok := d.Observe(e); ok {
+			return result, true
+		}
+
+		e.NextGeneration()
+	}
+
+	return d.Observe(e)
+}
+
+// sighting is a generation with the given hash of the cells.
+type sighting struct {
+	generation int
+	// cells are the alive cells relative to the position, so the generations
+	// with the same hash are compared cell by cell.
+	cells []engine.Point
+	// x and y is the position of the cells the hash is computed relative to.
+	x int
+	y int
+}
+
+// Detector remembers the hashes of the consecutive generations and finds the
+// first one that repeats an earlier generation. The hashes do not depend on
+// the position of the cells, so the moving patterns are found as well. On a
+// torus, the cells crossing the edges are found too. The generations with the
+// same hash are compared cell by cell, so a collision of the hashes is not
+// reported. The cells of the oldest generations are forgotten when there are
+// too many of them, so the longest period found is shorter for the large
+// populations.
+type Detector struct {
+	maxPeriod  int
+	maxCells   int
+	hashPoints func(points []engine.Point) uint64
+	last       int
+	sightings  map[uint64][]sighting
+	hashes     []uint64
+	cells      int
+	result     *Result
+}
+
+// Option configures the detector.
+type Option func(*Detector)
+
+// WithMaxCells sets the number of the alive cells kept of all remembered
+// generations. By default, DefaultMaxCells is used.
+func WithMaxCells(n int) Option {
+	return func(d *Detector) {
+		d.maxCells = max(n, 1)
+	}
+}
+
+// NewDetector creates a detector of the periods up to the given one.
+func NewDetector(maxPeriod int, opts ...Option) *Detector {
+	d := &Detector{
+		maxPeriod:  max(maxPeriod, 1),
+		maxCells:   DefaultMaxCells,
+		hashPoints: engine.Hash,
+		sightings:  make(map[uint64][]sighting),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Reset forgets all generations, so the cells are detected from scratch.
+func (d *Detector) Reset() {
+	clear(d.sightings)
+	d.hashes = d.hashes[:0]
+	d.cells = 0
+	d.result = nil
+}
+
+// Result returns how the cells repeat themselves. It returns false if they
+// are not found to repeat yet.
+func (d *Detector) Result() (Result, bool) {
+	if d.result == nil {
+		return Result{}, false
+	}
+
+	return *d.result, true
+}
+
+// Observe adds the current generation of the engine. If it does not follow
+// the last added one, the earlier generations are forgotten, because the
+// cells are edited or moved by many generations at once. It returns true
+// only for the generation that repeats an earlier one. The result stays known
+// while the cells keep repeating themselves, for example until a spaceship
+// hits the edge of the grid, or until the detector is reset.
+func (d *Detector) Observe(e engine.Engine) (Result, bool) {
+	generation := e.Generation()
+	if len(d.hashes) == 0 || generation != d.last+1 {
+		d.Reset()
+	}
+
+	d.last = generation
+
+	sum, cells, x, y := d.hash(e)
+	s, found := d.find(sum, cells)
+	d.add(sum, sighting{generation: generation, cells: cells, x: x, y: y})
+
+	if d.result != nil {
+		if found && (generation-s.generation)%d.result.Period == 0 {
+			return *d.result, false
+		}
+
+		d.result = nil
+	}
+
+	if !found {
+		return Result{}, false
+	}
+
+	dx, dy := x-s.x, y-s.y
+	if width, height, ok := torus(e); ok {
+		dx, dy = nearest(dx, width), nearest(dy, height)
+	}
+
+	d.result = &Result{Generation: s.generation, Period: generation - s.generation, DX: dx, DY: dy}
+
+	return *d.result, true
+}
+
+// find returns the earliest generation with the same cells.
+func (d *Detector) find(sum uint64, cells []engine.Point) (sighting, bool) {
+	for _, s := range d.sightings[sum] {
+		if slices.Equal(s.cells, cells) {
+			return s, true
+		}
+	}
+
+	return sighting{}, false
+}
+
+// add remembers the generation, and forgets the oldest ones while there are
+// more generations than the longest period or more cells than the limit. The
+// added generation is always kept.
+func (d *Detector) add(sum uint64, s sighting) {
+	d.sightings[sum] = append(d.sightings[sum], s)
+	d.hashes = append(d.hashes, sum)
+	d.cells += len(s.cells)
+
+	for len(d.hashes) > d.maxPeriod || d.cells > d.maxCells && len(d.hashes) > 1 {
+		// The oldest generation is the first one with its hash.
+		oldest := d.hashes[0]
+		d.hashes = d.hashes[1:]
+		d.cells -= len(d.sightings[oldest][0].cells)
+
+		if d.sightings[oldest] = d.sightings[oldest][1:]; len(d.sightings[oldest]) == 0 {
+			delete(d.sightings, oldest)
+		}
+	}
+}
+
+// hash returns the hash of the alive cells relative to their top left
+// corner, the cells relative to the corner, and the position of the corner.
+// On a torus, the corner is the first column and row after the widest empty
+// gap, so the cells crossing the edges are hashed as a whole. If there are
+// several such gaps, the smallest hash is used.
+func (d *Detector) hash(e engine.Engine) (uint64, []engine.Point, int, int) {
+	cells := engine.AliveCells(e)
+
+	width, height, ok := torus(e)
+	if !ok {
+		bounds := e.BoundingBox()
+		shifted := shift(cells, bounds.MinX, bounds.MinY, 0, 0)
+
+		return d.hashPoints(shifted), shifted, bounds.MinX, bounds.MinY
+	}
+
+	columns := make([]bool, width)
+	rows := make([]bool, height)
+
+	for _, p := range cells {
+		columns[p.X] = true
+		rows[p.Y] = true
+	}
+
+	var (
+		best        uint64
+		bestShifted []engine.Point
+	)
+
+	bestX, bestY := 0, 0
+
+	for i, x := range starts(columns) {
+		for j, y := range starts(rows) {
+			shifted := shift(cells, x, y, width, height)
+			if sum := d.hashPoints(shifted); i == 0 && j == 0 || sum < best {
+				best, bestShifted, bestX, bestY = sum, shifted, x, y
+			}
+		}
+	}
+
+	return best, bestShifted, bestX, bestY
+}
+
+// shift returns the cells relative to the position in the order of the rows.
+// If the size is positive, the cells are wrapped around it.
+func shift(cells []engine.Point, x, y, width, height int) []engine.Point {
+	shifted := make([]engine.Point, len(cells))
+	for i, p := range cells {
+		shifted[i] = engine.Point{X: p.X - x, Y: p.Y - y}
+
+		if width > 0 {
+			shifted[i] = engine.Point{X: mod(shifted[i].X, width), Y: mod(shifted[i].Y, height)}
+		}
+	}
+
+	slices.SortFunc(shifted, func(a, b engine.Point) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+
+	return shifted
+}
+
+// starts returns the lines that follow the widest gaps of empty lines around
+// the torus. If no line is empty, the first line is returned.
+func starts(occupied []bool) []int {
+	n := len(occupied)
+
+	widest := 0
+	var result []int
+
+	for i := range n {
+		if occupied[i] || !occupied[mod(i-1, n)] {
+			continue
+		}
+
+		// Measure the gap starting at the empty line i.
+		gap := 0
+		for gap < n && !occupied[mod(i+gap, n)] {
+			gap++
+		}
+
+		switch start := mod(i+gap, n); {
+		case gap > widest:
+			widest, result = gap, []int{start}
+		case gap == widest && len(result) < maxOffsets:
+			result = append(result, start)
+		}
+	}
+
+	if len(result) == 0 {
+		return []int{0}
+	}
+
+	return result
+}
+
+// torus returns the size of the grid if its edges are connected like a
+// torus.
+func torus(e engine.Engine) (int, int, bool) {
+	g, ok := e.(*grid.Grid)
+	if !ok || g.Topology() != grid.Torus {
+		return 0, 0, false
+	}
+
+	return g.Width(), g.Height(), true
+}
+
+// nearest returns the shortest movement around the torus of the given size.
+func nearest(d, size int) int {
+	d = mod(d, size)
+	if d > size/2 {
+		d -= size
+	}
+
+	return d
+}
+
+// fraction returns the number of cells per the number of generations in the
+// lowest terms, like "c/2" or "2c/5".
+func fraction(cells, generations int) string {
+	d := gcd(cells, generations)
+	cells, generations = cells/d, generations/d
+
+	switch {
+	case cells == 1 && generations == 1:
+		return "c"
+	case cells == 1:
+		return fmt.Sprintf("c/%d", generations)
+	case generations == 1:
+		return fmt.Sprintf("%dc", cells)
+	default:
+		return fmt.Sprintf("%dc/%d", cells, generations)
+	}
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func mod(a, b int) int {
+	return (a%b + b) % b
+}
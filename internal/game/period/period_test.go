@@ -0,0 +1,242 @@
+package period_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/period"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+var glider = []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}
+
+func TestDetector_Observe(t *testing.T) {
+	tt := []struct {
+		name     string
+		engine   engine.Engine
+		cells    []engine.Point
+		expected period.Result
+	}{
+		{
+			name:     "tromino becomes a block",
+			engine:   sparse.New(),
+			cells:    []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}},
+			expected: period.Result{Generation: 1, Period: 1},
+		},
+		{
+			name:     "blinker",
+			engine:   grid.New(5, 5),
+			cells:    []engine.Point{{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}},
+			expected: period.Result{Period: 2},
+		},
+		{
+			name:     "glider",
+			engine:   sparse.New(),
+			cells:    glider,
+			expected: period.Result{Period: 4, DX: 1, DY: 1},
+		},
+		{
+			name:     "glider crossing the edges of a torus",
+			engine:   grid.New(6, 5, grid.WithTopology(grid.Torus)),
+			cells:    []engine.Point{{X: 5, Y: 3}, {X: 0, Y: 4}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 0, Y: 0}},
+			expected: period.Result{Period: 4, DX: 1, DY: 1},
+		},
+		{
+			name:     "lightweight spaceship crossing the edge of a torus",
+			engine:   grid.New(12, 7, grid.WithTopology(grid.Torus)),
+			cells:    []engine.Point{{X: 11, Y: 1}, {X: 2, Y: 1}, {X: 10, Y: 2}, {X: 10, Y: 3}, {X: 2, Y: 3}, {X: 10, Y: 4}, {X: 11, Y: 4}, {X: 0, Y: 4}, {X: 1, Y: 4}},
+			expected: period.Result{Period: 4, DX: -2},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, p := range tc.cells {
+				tc.engine.SetCell(p.X, p.Y, cell.Alive)
+			}
+
+			d := period.NewDetector(100)
+
+			var found []period.Result
+
+			for range 40 {
+				if result, ok := d.Observe(tc.engine); ok {
+					found = append(found, result)
+				}
+
+				tc.engine.NextGeneration()
+			}
+
+			// The cells are reported once, but the result is kept.
+			assert.Equal(t, []period.Result{tc.expected}, found)
+
+			result, ok := d.Result()
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, result)
+			assert.Equal(t, tc.expected.Period == 1 && tc.expected.DX == 0, result.Static())
+			assert.Equal(t, tc.expected.DX != 0 || tc.expected.DY != 0, result.Moving())
+		})
+	}
+}
+
+func TestDetector_ObserveResets(t *testing.T) {
+	g := grid.New(10, 10)
+	for x := 1; x < 4; x++ {
+		g.SetCell(x, 2, cell.Alive)
+	}
+
+	d := period.NewDetector(100)
+	d.Observe(g)
+	g.NextGeneration()
+	d.Observe(g)
+
+	// An edit at the same generation forgets the earlier generations.
+	g.SetCell(8, 8, cell.Alive)
+	_, ok := d.Observe(g)
+	assert.False(t, ok)
+
+	// The skipped generations are not compared either.
+	engine.Advance(g, 2)
+	_, ok = d.Observe(g)
+	assert.False(t, ok)
+
+	// The periods longer than the limit are not found.
+	d = period.NewDetector(1)
+	for range 4 {
+		_, ok = d.Observe(g)
+		assert.False(t, ok)
+		g.NextGeneration()
+	}
+}
+
+func TestDetector_ObserveMaxCells(t *testing.T) {
+	tt := []struct {
+		maxCells int
+		expected bool
+	}{
+		// The blinker is found when both of its phases are kept.
+		{maxCells: 6, expected: true},
+		// Only the last phase is kept, so the blinker is never found.
+		{maxCells: 5, expected: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprint(tc.maxCells), func(t *testing.T) {
+			g := grid.New(5, 5)
+			for x := 1; x <= 3; x++ {
+				g.SetCell(x, 2, cell.Alive)
+			}
+
+			d := period.NewDetector(100, period.WithMaxCells(tc.maxCells))
+
+			found := false
+			for range 10 {
+				_, ok := d.Observe(g)
+				found = found || ok
+
+				g.NextGeneration()
+			}
+
+			assert.Equal(t, tc.expected, found)
+		})
+	}
+}
+
+func TestDetector_ObserveHashCollision(t *testing.T) {
+	// All generations get the same hash, so they are compared cell by cell.
+	e := grid.New(5, 5)
+	for x := 1; x <= 3; x++ {
+		e.SetCell(x, 2, cell.Alive)
+	}
+
+	d := period.NewDetectorWithHash(10, func([]engine.Point) uint64 { return 0 })
+	for range 2 {
+		_, ok := d.Observe(e)
+		assert.False(t, ok)
+
+		e.NextGeneration()
+	}
+
+	result, ok := d.Observe(e)
+	assert.True(t, ok)
+	assert.Equal(t, period.Result{Period: 2}, result)
+}
+
+func TestResult_Speed(t *testing.T) {
+	tt := []struct {
+		result   period.Result
+		expected string
+	}{
+		{result: period.Result{Period: 2}, expected: ""},
+		{result: period.Result{Period: 4, DX: 1, DY: -1}, expected: "c/4 diagonal"},
+		{result: period.Result{Period: 4, DX: -2}, expected: "c/2 orthogonal"},
+		{result: period.Result{Period: 5, DY: 2}, expected: "2c/5 orthogonal"},
+		{result: period.Result{Period: 1, DX: 1}, expected: "c orthogonal"},
+		{result: period.Result{Period: 6, DX: 1, DY: 2}, expected: "(2,1)c/6 oblique"},
+		{result: period.Result{Period: 12, DX: -4, DY: 2}, expected: "(2,1)c/6 oblique"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.result.Speed())
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	lwss := []engine.Point{{X: 1, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 4, Y: 2}, {X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}}
+
+	tt := []struct {
+		name     string
+		engine   engine.Engine
+		cells    []engine.Point
+		expected string
+	}{
+		{
+			name:     "glider on a grid",
+			engine:   grid.New(20, 20),
+			cells:    glider,
+			expected: "c/4 diagonal",
+		},
+		{
+			name:     "glider in an unbounded universe",
+			engine:   sparse.New(),
+			cells:    glider,
+			expected: "c/4 diagonal",
+		},
+		{
+			name:     "lightweight spaceship on a torus",
+			engine:   grid.New(8, 6, grid.WithTopology(grid.Torus)),
+			cells:    lwss,
+			expected: "c/2 orthogonal",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, p := range tc.cells {
+				tc.engine.SetCell(p.X, p.Y, cell.Alive)
+			}
+
+			result, ok := period.Find(tc.engine, 10)
+			assert.True(t, ok)
+			assert.Equal(t, 4, result.Period)
+			assert.Equal(t, tc.expected, result.Speed())
+			assert.Equal(t, 4, tc.engine.Generation())
+		})
+	}
+
+	// The glider does not repeat itself in two generations.
+	u := sparse.New()
+	for _, p := range glider {
+		u.SetCell(p.X, p.Y, cell.Alive)
+	}
+
+	_, ok := period.Find(u, 2)
+	assert.False(t, ok)
+}
@@ -0,0 +1,304 @@
+package render
+
+import "strings"
+
+const (
+	setGreenColor   = "\033[32m"
+	setYellowColor  = "\033[33m"
+	setMagentaColor = "\033[35m"
+	setCyanColor    = "\033[36m"
+	resetColor      = "\033[0m"
+)
+
+// Pixel is the state of a screen cell.
+type Pixel uint8
+
+const (
+	// Outside is a screen cell outside the bounded engine.
+	Outside Pixel = iota
+	// Dead is a screen cell without alive cells.
+	Dead
+	// Alive is a screen cell with at least one alive cell.
+	Alive
+	// CursorDead is a screen cell without alive cells under the keyboard
+	// cursor.
+	CursorDead
+	// CursorAlive is a screen cell with at least one alive cell under the
+	// keyboard cursor.
+	CursorAlive
+	// SelectedDead is a selected screen cell without alive cells.
+	SelectedDead
+	// SelectedAlive is a selected screen cell with at least one alive cell.
+	SelectedAlive
+	// Ghost is a screen cell where a pasted pattern puts an alive cell.
+	Ghost
+)
+
+// visible returns true if the screen cell is drawn, so the alive screen
+// cells, the cursor and the ghost of a pasted pattern are visible.
+func (p Pixel) visible() bool {
+	switch p {
+	case Alive, CursorDead, CursorAlive, SelectedAlive, Ghost:
+		return true
+	default:
+		return false
+	}
+}
+
+// color returns the color of the screen cell, or an empty string if it is
+// not colored.
+func (p Pixel) color() string {
+	switch p {
+	case Alive:
+		return setGreenColor
+	case CursorDead, CursorAlive:
+		return setYellowColor
+	case SelectedDead, SelectedAlive:
+		return setCyanColor
+	case Ghost:
+		return setMagentaColor
+	default:
+		return ""
+	}
+}
+
+// visible returns the screen cells that are drawn.
+func visible(pixels ...Pixel) []Pixel {
+	var drawn []Pixel
+	for _, p := range pixels {
+		if p.visible() {
+			drawn = append(drawn, p)
+		}
+	}
+
+	return drawn
+}
+
+// colorOf returns the color of a character drawing the screen cells. The
+// cursor is the most visible, then the ghost, the selection and the alive
+// screen cells.
+func colorOf(pixels ...Pixel) string {
+	for _, c := range []string{setYellowColor, setMagentaColor, setCyanColor, setGreenColor} {
+		for _, p := range pixels {
+			if p.color() == c {
+				return c
+			}
+		}
+	}
+
+	return ""
+}
+
+// Renderer draws the screen cells with terminal characters. A character can
+// draw several screen cells and take several terminal columns.
+type Renderer interface {
+	// Name returns the name of the renderer.
+	Name() string
+	// Scale returns the number of columns and rows of screen cells drawn by
+	// a character.
+	Scale() (columns, rows int)
+	// CharWidth returns the number of terminal columns taken by a character.
+	CharWidth() int
+	// Render returns the lines of characters drawing the screen cells. The
+	// missing screen cells of the last characters are outside.
+	Render(pixels [][]Pixel) []string
+}
+
+// All returns all renderers in the order they are switched.
+func All() []Renderer {
+	return []Renderer{Blocks{}, HalfBlocks{}, Braille{}}
+}
+
+// Blocks draws a screen cell with a square and a space after it.
+type Blocks struct{}
+
+// Name returns the name of the renderer.
+func (Blocks) Name() string {
+	return "blocks"
+}
+
+// Scale returns the number of columns and rows of screen cells drawn by a character.
+func (Blocks) Scale() (int, int) {
+	return 1, 1
+}
+
+// CharWidth returns the number of terminal columns taken by a character.
+func (Blocks) CharWidth() int {
+	return 2
+}
+
+// Render returns the lines of characters drawing the screen cells.
+func (Blocks) Render(pixels [][]Pixel) []string {
+	lines := make([]string, len(pixels))
+
+	for y, row := range pixels {
+		var sb strings.Builder
+
+		for _, pixel := range row {
+			switch pixel {
+			case Alive, SelectedAlive, Ghost:
+				write(&sb, pixel.color(), "■ ")
+			case CursorAlive:
+				write(&sb, pixel.color(), "▣ ")
+			case CursorDead:
+				write(&sb, pixel.color(), "▢ ")
+			case Dead, SelectedDead:
+				write(&sb, pixel.color(), "□ ")
+			default:
+				sb.WriteString("  ")
+			}
+		}
+
+		lines[y] = sb.String()
+	}
+
+	return lines
+}
+
+// HalfBlocks draws two screen cells, one above another, with a half block.
+type HalfBlocks struct{}
+
+// Name returns the name of the renderer.
+func (HalfBlocks) Name() string {
+	return "half blocks"
+}
+
+// Scale returns the number of columns and rows of screen cells drawn by a character.
+func (HalfBlocks) Scale() (int, int) {
+	return 1, 2
+}
+
+// CharWidth returns the number of terminal columns taken by a character.
+func (HalfBlocks) CharWidth() int {
+	return 1
+}
+
+// Render returns the lines of characters drawing the screen cells.
+func (HalfBlocks) Render(pixels [][]Pixel) []string {
+	lines := make([]string, (len(pixels)+1)/2)
+
+	for y := range lines {
+		var sb strings.Builder
+
+		for x := range width(pixels) {
+			top, bottom := pixelAt(pixels, x, 2*y), pixelAt(pixels, x, 2*y+1)
+			color := colorOf(visible(top, bottom)...)
+
+			switch {
+			case top.visible() && bottom.visible():
+				write(&sb, color, "█")
+			case top.visible():
+				write(&sb, color, "▀")
+			case bottom.visible():
+				write(&sb, color, "▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+
+		lines[y] = sb.String()
+	}
+
+	return lines
+}
+
+// Braille draws 2x4 screen cells with a braille pattern.
+type Braille struct{}
+
+// brailleDots are the bits of the braille dots for the screen cells of a
+// character, indexed by the row and the column.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// Name returns the name of the renderer.
+func (Braille) Name() string {
+	return "braille"
+}
+
+// Scale returns the number of columns and rows of screen cells drawn by a character.
+func (Braille) Scale() (int, int) {
+	return 2, 4
+}
+
+// CharWidth returns the number of terminal columns taken by a character.
+func (Braille) CharWidth() int {
+	return 1
+}
+
+// Render returns the lines of characters drawing the screen cells. The
+// characters without alive screen cells are blank braille patterns inside
+// the bounded engine, and spaces outside it.
+func (Braille) Render(pixels [][]Pixel) []string {
+	lines := make([]string, (len(pixels)+3)/4)
+
+	for y := range lines {
+		var sb strings.Builder
+
+		for x := 0; x < width(pixels); x += 2 {
+			dots := rune(0)
+			inside := false
+
+			var drawn []Pixel
+			for dy, row := range brailleDots {
+				for dx, dot := range row {
+					pixel := pixelAt(pixels, x+dx, 4*y+dy)
+					inside = inside || pixel != Outside
+
+					if pixel.visible() {
+						dots |= dot
+						drawn = append(drawn, pixel)
+					}
+				}
+			}
+
+			switch {
+			case dots != 0:
+				write(&sb, colorOf(drawn...), string(0x2800+dots))
+			case inside:
+				sb.WriteRune(0x2800)
+			default:
+				sb.WriteString(" ")
+			}
+		}
+
+		lines[y] = sb.String()
+	}
+
+	return lines
+}
+
+// write writes the characters in the color. The characters are not colored
+// if the color is empty.
+func write(sb *strings.Builder, color, s string) {
+	if color == "" {
+		sb.WriteString(s)
+		return
+	}
+
+	sb.WriteString(color)
+	sb.WriteString(s)
+	sb.WriteString(resetColor)
+}
+
+// width returns the number of columns of screen cells.
+func width(pixels [][]Pixel) int {
+	if len(pixels) == 0 {
+		return 0
+	}
+
+	return len(pixels[0])
+}
+
+// pixelAt returns the screen cell in the x-th column and y-th row. The
+// screen cells outside the slice are outside.
+func pixelAt(pixels [][]Pixel, x, y int) Pixel {
+	if y >= len(pixels) || x >= len(pixels[y]) {
+		return Outside
+	}
+
+	return pixels[y][x]
+}
@@ -0,0 +1,153 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/render"
+)
+
+const (
+	o = render.Outside
+	d = render.Dead
+	a = render.Alive
+)
+
+// pixels is the glider with a column of dead and outside screen cells on the right.
+var pixels = [][]render.Pixel{
+	{d, a, d, d, o},
+	{d, d, a, d, o},
+	{a, a, a, d, o},
+}
+
+func TestRenderers(t *testing.T) {
+	tt := []struct {
+		renderer        render.Renderer
+		expectedColumns int
+		expectedRows    int
+		expectedWidth   int
+		expected        []string
+	}{
+		{
+			renderer:        render.Blocks{},
+			expectedColumns: 1,
+			expectedRows:    1,
+			expectedWidth:   2,
+			expected: []string{
+				"□ ■ □ □   ",
+				"□ □ ■ □   ",
+				"■ ■ ■ □   ",
+			},
+		},
+		{
+			renderer:        render.HalfBlocks{},
+			expectedColumns: 1,
+			expectedRows:    2,
+			expectedWidth:   1,
+			expected: []string{
+				" ▀▄  ",
+				"▀▀▀  ",
+			},
+		},
+		{
+			renderer:        render.Braille{},
+			expectedColumns: 2,
+			expectedRows:    4,
+			expectedWidth:   1,
+			expected: []string{
+				// The left character has the dots 3, 4 and 6, and the right one has 2 and 3.
+				"⠬⠆ ",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.renderer.Name(), func(t *testing.T) {
+			columns, rows := tc.renderer.Scale()
+			assert.Equal(t, tc.expectedColumns, columns)
+			assert.Equal(t, tc.expectedRows, rows)
+			assert.Equal(t, tc.expectedWidth, tc.renderer.CharWidth())
+			assert.Equal(t, tc.expected, withoutColors(tc.renderer.Render(pixels)))
+		})
+	}
+}
+
+func TestBraille_BlankCharacters(t *testing.T) {
+	lines := render.Braille{}.Render([][]render.Pixel{{d, d, o, o}})
+	assert.Equal(t, []string{"⠀ "}, lines)
+}
+
+func TestRenderers_Highlights(t *testing.T) {
+	const (
+		cd      = render.CursorDead
+		ca      = render.CursorAlive
+		sd      = render.SelectedDead
+		sa      = render.SelectedAlive
+		g       = render.Ghost
+		green   = "\033[32m"
+		yellow  = "\033[33m"
+		magenta = "\033[35m"
+		cyan    = "\033[36m"
+		reset   = "\033[0m"
+	)
+
+	tt := []struct {
+		renderer render.Renderer
+		pixels   [][]render.Pixel
+		expected []string
+	}{
+		{
+			renderer: render.Blocks{},
+			pixels:   [][]render.Pixel{{cd, ca}},
+			expected: []string{yellow + "▢ " + reset + yellow + "▣ " + reset},
+		},
+		{
+			renderer: render.Blocks{},
+			pixels:   [][]render.Pixel{{sd, sa, g}},
+			expected: []string{cyan + "□ " + reset + cyan + "■ " + reset + magenta + "■ " + reset},
+		},
+		{
+			// The character is colored by the drawn screen cells only.
+			renderer: render.HalfBlocks{},
+			pixels:   [][]render.Pixel{{a, g}, {sd, a}},
+			expected: []string{green + "▀" + reset + magenta + "█" + reset},
+		},
+		{
+			// The cursor is drawn even if the screen cell is dead.
+			renderer: render.HalfBlocks{},
+			pixels:   [][]render.Pixel{{cd}, {d}},
+			expected: []string{yellow + "▀" + reset},
+		},
+		{
+			renderer: render.Braille{},
+			pixels:   [][]render.Pixel{{d, ca}, {a, d}},
+			expected: []string{yellow + "⠊" + reset},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.renderer.Name(), func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.renderer.Render(tc.pixels))
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	var names []string
+	for _, r := range render.All() {
+		names = append(names, r.Name())
+	}
+
+	assert.Equal(t, []string{"blocks", "half blocks", "braille"}, names)
+}
+
+func withoutColors(lines []string) []string {
+	for i, line := range lines {
+		line = strings.ReplaceAll(line, "\033[32m", "")
+		lines[i] = strings.ReplaceAll(line, "\033[0m", "")
+	}
+
+	return lines
+}
@@ -0,0 +1,87 @@
+package game
+
+const (
+	// historyLimit is the number of the latest states of the cells that can
+	// be restored.
+	historyLimit = 1000
+	// maxHistoryArea is the largest bounding box of the alive cells that is
+	// recorded. The larger patterns are too slow to compare, so the history
+	// and the timeline are cleared instead.
+	maxHistoryArea = 1 << 20
+)
+
+// record adds the current state of the cells to the history, the timeline
+// and the detector of the periods.
+func (g *Game) record() {
+	bounds := g.universe.BoundingBox()
+	if bounds.Width()*bounds.Height() > maxHistoryArea {
+		g.history.Clear()
+		g.timeline.Clear()
+		g.detector.Reset()
+
+		return
+	}
+
+	g.history.Record(g.universe)
+	g.timeline.Record(g.universe)
+	g.detect()
+}
+
+// restored records the cells restored from the history in the timeline. The
+// periods are detected from scratch.
+func (g *Game) restored() {
+	g.timeline.Record(g.universe)
+	g.detector.Reset()
+}
+
+// undo moves the cells to the previous recorded state.
+func (g *Game) undo() {
+	g.pause()
+	g.status = ""
+
+	if !g.history.Undo(g.universe) {
+		g.status = "Nothing to undo."
+		return
+	}
+
+	g.restored()
+}
+
+// redo moves the cells to the next recorded state.
+func (g *Game) redo() {
+	g.pause()
+	g.status = ""
+
+	if !g.history.Redo(g.universe) {
+		g.status = "Nothing to redo."
+		return
+	}
+
+	g.restored()
+}
+
+// rewind moves the cells to the state the game was started from last time.
+func (g *Game) rewind() {
+	g.pause()
+	g.status = ""
+
+	if !g.history.Rewind(g.universe) {
+		g.status = "Nothing to rewind to."
+		return
+	}
+
+	g.restored()
+}
+
+// back moves the cells to the previous recorded generation.
+func (g *Game) back() {
+	g.pause()
+	g.status = ""
+
+	if !g.history.Back(g.universe) {
+		g.status = "No previous generation is recorded."
+		return
+	}
+
+	g.restored()
+}
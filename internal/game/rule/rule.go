@@ -0,0 +1,202 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxNeighbors is the number of neighbors a cell has in the Moore neighborhood.
+const maxNeighbors = 8
+
+var (
+	// Conway is the rule of the original Conway's Game of Life.
+	Conway = MustParse("B3/S23")
+	// HighLife is similar to Conway's rule, but has a replicator.
+	HighLife = MustParse("B36/S23")
+	// DayAndNight is symmetric under inverting the alive and dead cells.
+	DayAndNight = MustParse("B3678/S34678")
+	// Seeds is the rule in which every alive cell dies in each generation.
+	Seeds = MustParse("B2/S")
+)
+
+// Rule represents an outer-totalistic Life-like rule. It defines the numbers
+// of alive neighbors for which a dead cell is born and an alive cell survives.
+type Rule struct {
+	// birth has the n-th bit set if a dead cell with n alive neighbors becomes alive.
+	birth uint16
+	// survival has the n-th bit set if an alive cell with n alive neighbors stays alive.
+	survival uint16
+}
+
+// Parse parses a rule in the B/S notation (e.g. "B36/S23") or in the S/B
+// notation (e.g. "23/36"). The letters are case-insensitive.
+func Parse(s string) (Rule, error) {
+	rulestring := strings.ToUpper(strings.TrimSpace(s))
+	if rulestring == "" {
+		return Rule{}, fmt.Errorf("invalid rule %q: empty rule", s)
+	}
+
+	if strings.ContainsAny(rulestring, "BS") {
+		return parseBS(s, rulestring)
+	}
+
+	return parseSB(s, rulestring)
+}
+
+// MustParse is like Parse but panics if the rule cannot be parsed.
+func MustParse(s string) Rule {
+	r, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Born returns true if a dead cell with the given number of alive neighbors becomes alive.
+func (r Rule) Born(aliveNeighbors int) bool {
+	return hasBit(r.birth, aliveNeighbors)
+}
+
+// Survives returns true if an alive cell with the given number of alive neighbors stays alive.
+func (r Rule) Survives(aliveNeighbors int) bool {
+	return hasBit(r.survival, aliveNeighbors)
+}
+
+// Next returns the state of the cell in the next generation.
+func (r Rule) Next(alive bool, aliveNeighbors int) bool {
+	if alive {
+		return r.Survives(aliveNeighbors)
+	}
+
+	return r.Born(aliveNeighbors)
+}
+
+// String returns the rule in the B/S notation.
+func (r Rule) String() string {
+	return "B" + digits(r.birth) + "/S" + digits(r.survival)
+}
+
+// StringSB returns the rule in the S/B notation.
+func (r Rule) StringSB() string {
+	return digits(r.survival) + "/" + digits(r.birth)
+}
+
+// parseBS parses the B/S notation, the parts can be separated by a slash.
+func parseBS(s, rulestring string) (Rule, error) {
+	var r Rule
+
+	var seenBirth, seenSurvival bool
+
+	for _, part := range splitBS(rulestring) {
+		if part == "" {
+			return Rule{}, fmt.Errorf("invalid rule %q: empty conditions", s)
+		}
+
+		mask, err := parseDigits(part[1:])
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid rule %q: %w", s, err)
+		}
+
+		switch part[0] {
+		case 'B':
+			if seenBirth {
+				return Rule{}, fmt.Errorf("invalid rule %q: duplicate birth conditions", s)
+			}
+
+			seenBirth = true
+			r.birth = mask
+		case 'S':
+			if seenSurvival {
+				return Rule{}, fmt.Errorf("invalid rule %q: duplicate survival conditions", s)
+			}
+
+			seenSurvival = true
+			r.survival = mask
+		default:
+			return Rule{}, fmt.Errorf("invalid rule %q: expected 'B' or 'S', got %q", s, part[0])
+		}
+	}
+
+	if !seenBirth || !seenSurvival {
+		return Rule{}, fmt.Errorf("invalid rule %q: both birth and survival conditions are required", s)
+	}
+
+	return r, nil
+}
+
+// splitBS splits the rulestring into parts starting with a letter.
+func splitBS(rulestring string) []string {
+	var parts []string
+
+	for _, part := range strings.Split(rulestring, "/") {
+		start := 0
+		for i := 1; i < len(part); i++ {
+			if part[i] == 'B' || part[i] == 'S' {
+				parts = append(parts, part[start:i])
+				start = i
+			}
+		}
+
+		parts = append(parts, part[start:])
+	}
+
+	return parts
+}
+
+// parseSB parses the S/B notation, where the survival conditions go first.
+func parseSB(s, rulestring string) (Rule, error) {
+	survival, birth, found := strings.Cut(rulestring, "/")
+	if !found {
+		return Rule{}, fmt.Errorf("invalid rule %q: expected survival and birth conditions separated by '/'", s)
+	}
+
+	survivalMask, err := parseDigits(survival)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %w", s, err)
+	}
+
+	birthMask, err := parseDigits(birth)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %w", s, err)
+	}
+
+	return Rule{birth: birthMask, survival: survivalMask}, nil
+}
+
+// parseDigits converts a list of neighbor counts into a bit mask.
+func parseDigits(s string) (uint16, error) {
+	var mask uint16
+
+	for _, ch := range s {
+		if ch < '0' || ch > '0'+maxNeighbors {
+			return 0, fmt.Errorf("unexpected character %q", ch)
+		}
+
+		mask |= 1 << (ch - '0')
+	}
+
+	return mask, nil
+}
+
+// digits converts a bit mask into a list of neighbor counts.
+func digits(mask uint16) string {
+	var sb strings.Builder
+
+	for n := 0; n <= maxNeighbors; n++ {
+		if hasBit(mask, n) {
+			sb.WriteByte(byte('0' + n))
+		}
+	}
+
+	return sb.String()
+}
+
+// hasBit returns true if the n-th bit of the mask is set.
+func hasBit(mask uint16, n int) bool {
+	if n < 0 || n > maxNeighbors {
+		return false
+	}
+
+	return mask&(1<<n) != 0
+}
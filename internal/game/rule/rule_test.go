@@ -0,0 +1,194 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+func TestParse(t *testing.T) {
+	tt := []struct {
+		name       string
+		rulestring string
+		expected   string
+	}{
+		{
+			name:       "B/S notation",
+			rulestring: "B3/S23",
+			expected:   "B3/S23",
+		},
+		{
+			name:       "B/S notation with lowercase letters",
+			rulestring: "b36/s23",
+			expected:   "B36/S23",
+		},
+		{
+			name:       "B/S notation without a slash",
+			rulestring: "B3678S34678",
+			expected:   "B3678/S34678",
+		},
+		{
+			name:       "B/S notation with survival conditions first",
+			rulestring: "S23/B3",
+			expected:   "B3/S23",
+		},
+		{
+			name:       "B/S notation with empty survival conditions",
+			rulestring: "B2/S",
+			expected:   "B2/S",
+		},
+		{
+			name:       "B/S notation with unordered neighbor counts",
+			rulestring: "B63/S32",
+			expected:   "B36/S23",
+		},
+		{
+			name:       "S/B notation",
+			rulestring: "23/36",
+			expected:   "B36/S23",
+		},
+		{
+			name:       "S/B notation with empty survival conditions",
+			rulestring: "/2",
+			expected:   "B2/S",
+		},
+		{
+			name:       "surrounding spaces are ignored",
+			rulestring: "  B3/S23 ",
+			expected:   "B3/S23",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := rule.Parse(tc.rulestring)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, r.String())
+		})
+	}
+}
+
+func TestRule_StringSB(t *testing.T) {
+	assert.Equal(t, "23/3", rule.Conway.StringSB())
+	assert.Equal(t, "23/36", rule.HighLife.StringSB())
+	assert.Equal(t, "/2", rule.Seeds.StringSB())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tt := []struct {
+		name       string
+		rulestring string
+	}{
+		{
+			name:       "empty rule",
+			rulestring: "",
+		},
+		{
+			name:       "missing survival conditions",
+			rulestring: "B3",
+		},
+		{
+			name:       "missing birth conditions",
+			rulestring: "S23",
+		},
+		{
+			name:       "duplicate birth conditions",
+			rulestring: "B3/B6/S23",
+		},
+		{
+			name:       "neighbor count is out of range",
+			rulestring: "B39/S23",
+		},
+		{
+			name:       "unexpected character",
+			rulestring: "B3/S2x",
+		},
+		{
+			name:       "empty conditions",
+			rulestring: "B3//S23",
+		},
+		{
+			name:       "S/B notation without a slash",
+			rulestring: "233",
+		},
+		{
+			name:       "unknown letter",
+			rulestring: "B3/S23/C4",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := rule.Parse(tc.rulestring)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	tt := []struct {
+		name           string
+		rule           rule.Rule
+		alive          bool
+		aliveNeighbors int
+		expected       bool
+	}{
+		{
+			name:           "the dead cell should be alive if there are three alive neighbors in Conway's rule",
+			rule:           rule.Conway,
+			alive:          false,
+			aliveNeighbors: 3,
+			expected:       true,
+		},
+		{
+			name:           "the dead cell should be dead if there are six alive neighbors in Conway's rule",
+			rule:           rule.Conway,
+			alive:          false,
+			aliveNeighbors: 6,
+			expected:       false,
+		},
+		{
+			name:           "the dead cell should be alive if there are six alive neighbors in HighLife",
+			rule:           rule.HighLife,
+			alive:          false,
+			aliveNeighbors: 6,
+			expected:       true,
+		},
+		{
+			name:           "the alive cell should be alive if there are two alive neighbors in Conway's rule",
+			rule:           rule.Conway,
+			alive:          true,
+			aliveNeighbors: 2,
+			expected:       true,
+		},
+		{
+			name:           "the alive cell should be dead if there are two alive neighbors in Seeds",
+			rule:           rule.Seeds,
+			alive:          true,
+			aliveNeighbors: 2,
+			expected:       false,
+		},
+		{
+			name:           "the alive cell should be alive if there are eight alive neighbors in Day & Night",
+			rule:           rule.DayAndNight,
+			alive:          true,
+			aliveNeighbors: 8,
+			expected:       true,
+		},
+		{
+			name:           "the neighbor count out of range should never make the cell alive",
+			rule:           rule.DayAndNight,
+			alive:          true,
+			aliveNeighbors: 9,
+			expected:       false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rule.Next(tc.alive, tc.aliveNeighbors))
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/mouse"
+	"github.com/ivanlemeshev/gameoflife/internal/game/pattern"
+)
+
+// maxFillArea is the largest number of cells filled at once.
+const maxFillArea = 1 << 20
+
+// selection is the rectangle of cells between two corners, both included.
+type selection struct {
+	// anchor is the corner where the selection is started.
+	anchor engine.Point
+	// corner is the opposite corner that follows the mouse or the cursor.
+	corner engine.Point
+}
+
+// rect returns the selected cells.
+func (s selection) rect() engine.Rect {
+	return engine.Rect{
+		MinX: min(s.anchor.X, s.corner.X),
+		MinY: min(s.anchor.Y, s.corner.Y),
+		MaxX: max(s.anchor.X, s.corner.X) + 1,
+		MaxY: max(s.anchor.Y, s.corner.Y) + 1,
+	}
+}
+
+// toggleSelecting starts a new selection, or stops changing the current one.
+// If the cursor is shown, the selection is started under the cursor and
+// follows it.
+func (g *Game) toggleSelecting() {
+	if g.selecting {
+		g.selecting = false
+		return
+	}
+
+	g.selecting = true
+	g.pasting = false
+	g.selection = nil
+
+	if g.cursor != nil {
+		g.selection = &selection{anchor: g.cursor.position, corner: g.cursor.position}
+	}
+}
+
+// copySelection copies the selected cells to the clipboard and to the system
+// clipboard as RLE text. It returns false if nothing is selected.
+func (g *Game) copySelection() bool {
+	if g.selection == nil {
+		g.status = "Nothing is selected."
+		return false
+	}
+
+	r := g.selection.rect()
+	g.clipboard = pattern.FromRect(g.universe, r)
+	g.copyToSystemClipboard(g.clipboard)
+	g.status = fmt.Sprintf("Copied %dx%d cells.", r.Width(), r.Height())
+
+	return true
+}
+
+// cutSelection copies the selected cells to the clipboard and clears them.
+func (g *Game) cutSelection() {
+	if g.copySelection() {
+		g.clearSelection()
+	}
+}
+
+// clearSelection makes the selected cells dead.
+func (g *Game) clearSelection() {
+	if g.selection == nil {
+		g.status = "Nothing is selected."
+		return
+	}
+
+	g.pause()
+
+	bounds := g.universe.BoundingBox().Intersect(g.selection.rect())
+	for y := bounds.MinY; y < bounds.MaxY; y++ {
+		for x := bounds.MinX; x < bounds.MaxX; x++ {
+			g.universe.SetCell(x, y, cell.Dead)
+		}
+	}
+
+	g.record()
+}
+
+// fillSelection makes the selected cells alive.
+func (g *Game) fillSelection() {
+	if g.selection == nil {
+		g.status = "Nothing is selected."
+		return
+	}
+
+	r := g.selection.rect()
+	if board, bounded := g.board(); bounded {
+		r = r.Intersect(board)
+	}
+
+	if r.Width()*r.Height() > maxFillArea {
+		g.status = "The selection is too large to fill."
+		return
+	}
+
+	g.pause()
+
+	for y := r.MinY; y < r.MaxY; y++ {
+		for x := r.MinX; x < r.MaxX; x++ {
+			g.universe.SetCell(x, y, cell.Alive)
+		}
+	}
+
+	g.record()
+}
+
+// togglePasting shows the ghost of the clipboard that follows the mouse or
+// the cursor, or hides it.
+func (g *Game) togglePasting() {
+	if g.pasting {
+		g.pasting = false
+		return
+	}
+
+	if g.clipboard == nil {
+		g.status = "The clipboard is empty."
+		return
+	}
+
+	g.startPasting()
+}
+
+// startPasting shows the ghost of the clipboard under the cursor, or in the
+// center of the viewport if the cursor is hidden.
+func (g *Game) startPasting() {
+	g.pasting = true
+	g.selecting = false
+
+	if g.cursor != nil {
+		g.pastePosition = g.cursor.position
+		return
+	}
+
+	center := g.viewport.Center()
+	g.pastePosition = engine.Point{X: center.X - g.clipboard.Width/2, Y: center.Y - g.clipboard.Height/2}
+}
+
+// stamp sets the alive cells of the clipboard at the ghost. The other cells
+// are not changed.
+func (g *Game) stamp() {
+	g.pause()
+	g.clipboard.Place(g.universe, g.pastePosition.X, g.pastePosition.Y)
+	g.record()
+}
+
+// transformClipboard replaces the clipboard with the transformed one.
+func (g *Game) transformClipboard(transform func(*pattern.Pattern) *pattern.Pattern) {
+	if g.clipboard == nil {
+		g.status = "The clipboard is empty."
+		return
+	}
+
+	g.clipboard = transform(g.clipboard)
+}
+
+// copyToSystemClipboard writes the pattern as RLE text to the system
+// clipboard with the OSC 52 escape sequence, which is supported by most
+// terminals, even over SSH.
+func (g *Game) copyToSystemClipboard(p *pattern.Pattern) {
+	var sb strings.Builder
+	if err := pattern.WriteRLE(&sb, p); err != nil {
+		g.status = fmt.Sprintf("Failed to copy the cells: %v", err)
+		return
+	}
+
+	if _, err := osc52.New(sb.String()).WriteTo(g.systemClipboard); err != nil {
+		g.status = fmt.Sprintf("Failed to copy the cells to the system clipboard: %v", err)
+	}
+}
+
+// pasteText puts the pattern pasted into the terminal into the clipboard and
+// shows its ghost. The text can be in any supported pattern format.
+func (g *Game) pasteText(text string) {
+	p, err := pattern.Read(strings.NewReader(text))
+	if err != nil {
+		g.status = fmt.Sprintf("The pasted text is not a pattern: %v", err)
+		return
+	}
+
+	g.clipboard = p
+	g.startPasting()
+	g.status = fmt.Sprintf("Pasted %dx%d cells.", p.Width, p.Height)
+}
+
+// followCursor moves the corner of the selection or the ghost of the
+// clipboard to the cell.
+func (g *Game) followCursor(p engine.Point) {
+	switch {
+	case g.pasting:
+		g.pastePosition = p
+	case g.selecting && g.selection != nil:
+		g.selection.corner = p
+	}
+}
+
+// handleSelectionMouseEvent moves the ghost of the clipboard with the mouse
+// and stamps it with a click, or selects the cells by dragging with the left
+// button. It returns false if the event is not about the selection.
+func (g *Game) handleSelectionMouseEvent(msg tea.MouseMsg) bool {
+	p, ok := g.cellUnderMouse(msg)
+	if !ok {
+		return false
+	}
+
+	switch {
+	case g.pasting && mouse.IsMotion(msg):
+		g.pastePosition = p
+	case g.pasting && mouse.IsLeftButtonPressed(msg):
+		g.pastePosition = p
+		g.stamp()
+	case g.selecting && mouse.IsLeftButtonPressed(msg):
+		g.selection = &selection{anchor: p, corner: p}
+	case g.selecting && g.selection != nil && mouse.IsDragged(msg):
+		g.selection.corner = p
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ghostScreenCells returns which screen cells show at least one alive cell of
+// the pasted clipboard.
+func (g *Game) ghostScreenCells() [][]bool {
+	v := g.viewport
+
+	ghost := make([][]bool, v.Height)
+	for y := range ghost {
+		ghost[y] = make([]bool, v.Width)
+	}
+
+	if !g.pasting {
+		return ghost
+	}
+
+	// A cell takes several screen cells when zoomed in.
+	size := 1 << max(v.Zoom, 0)
+
+	for _, p := range g.clipboard.Cells {
+		sx, sy, ok := v.ScreenCell(engine.Point{X: g.pastePosition.X + p.X, Y: g.pastePosition.Y + p.Y})
+		if !ok {
+			continue
+		}
+
+		for y := sy; y < min(sy+size, v.Height); y++ {
+			for x := sx; x < min(sx+size, v.Width); x++ {
+				ghost[y][x] = true
+			}
+		}
+	}
+
+	return ghost
+}
+
+// selectionStatus returns the selection or the clipboard being pasted for
+// the footer.
+func (g *Game) selectionStatus() string {
+	switch {
+	case g.pasting:
+		return fmt.Sprintf(" | Paste: %dx%d", g.clipboard.Width, g.clipboard.Height)
+	case g.selection != nil:
+		r := g.selection.rect()
+		return fmt.Sprintf(" | Selection: %dx%d", r.Width(), r.Height())
+	case g.selecting:
+		return " | Select"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
+)
+
+// defaultSoupDensity is the probability of a cell to be alive in the soups
+// when the density is not set.
+const defaultSoupDensity = 0.5
+
+// soupFill is the settings the last soup was filled with. They are kept
+// apart from the current settings, which can be changed before the next soup.
+type soupFill struct {
+	seed     uint64
+	density  float64
+	symmetry soup.Symmetry
+	region   engine.Rect
+}
+
+// fillSoup fills the soup region with a new random soup. The seed is
+// increased with every soup, so each one can be reproduced from the command
+// line by its seed.
+func (g *Game) fillSoup() {
+	r := g.symmetry.Rect(g.soupRegion())
+	if r.Width()*r.Height() > maxFillArea {
+		g.status = "The soup region is too large to fill."
+		return
+	}
+
+	if g.lastSoup != nil {
+		g.seed++
+	}
+
+	g.pause()
+	g.addSoup()
+	g.record()
+
+	g.status = fmt.Sprintf(
+		"Filled %dx%d cells with a %s soup of density %g and seed %d.",
+		r.Width(), r.Height(), g.symmetry, g.soupDensity(), g.seed,
+	)
+}
+
+// addSoup fills the soup region with the soup of the current seed and
+// remembers its settings.
+func (g *Game) addSoup() {
+	g.lastSoup = &soupFill{seed: g.seed, density: g.soupDensity(), symmetry: g.symmetry, region: g.soupRegion()}
+	soup.Fill(g.universe, g.lastSoup.region, g.lastSoup.seed, g.lastSoup.density, g.lastSoup.symmetry)
+}
+
+// soupRegion returns the cells the soup is filled into: the selection, the
+// soup region, the centered square of the soup size, the bounded engine or the
+// visible cells of the unbounded engine.
+func (g *Game) soupRegion() engine.Rect {
+	board, bounded := g.board()
+	if !bounded {
+		board = g.viewport.Bounds()
+	}
+
+	switch {
+	case g.selection != nil:
+		return g.selection.rect()
+	case !g.soupArea.Empty():
+		return g.soupArea
+	case g.soupSize > 0:
+		center := g.viewport.Center()
+		minX, minY := center.X-g.soupSize/2, center.Y-g.soupSize/2
+
+		return engine.Rect{MinX: minX, MinY: minY, MaxX: minX + g.soupSize, MaxY: minY + g.soupSize}
+	default:
+		return board
+	}
+}
+
+// soupDensity returns the probability of a cell to be alive in the soups.
+func (g *Game) soupDensity() float64 {
+	if g.density > 0 {
+		return g.density
+	}
+
+	return defaultSoupDensity
+}
+
+// cycleSymmetry switches to the next symmetry of the soups.
+func (g *Game) cycleSymmetry() {
+	symmetries := soup.Symmetries()
+	g.symmetry = symmetries[(int(g.symmetry)+1)%len(symmetries)]
+	g.status = fmt.Sprintf("The soups are %s symmetric.", g.symmetry)
+}
+
+// soupStatus returns the seed, the density, the symmetry and the region of
+// the last soup for the footer, so the soup can be reproduced from the command
+// line.
+func (g *Game) soupStatus() string {
+	if g.lastSoup == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		" | Seed: %d | Density: %g | Symmetry: %s | Region: %s",
+		g.lastSoup.seed, g.lastSoup.density, g.lastSoup.symmetry, g.lastSoup.region,
+	)
+}
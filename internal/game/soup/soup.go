@@ -0,0 +1,149 @@
+// Package soup fills the cells with random soups that are reproducible by the
+// seed and can be symmetric.
+package soup
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+// Symmetry is the symmetry of the soup inside its rectangle. The names follow
+// the ones used by the soup searches.
+type Symmetry int
+
+const (
+	// C1 is not symmetric.
+	C1 Symmetry = iota
+	// C2 is symmetric under the rotation by 180 degrees.
+	C2
+	// C4 is symmetric under the rotation by 90 degrees.
+	C4
+	// D4 is symmetric under the horizontal and the vertical reflection.
+	D4
+	// D8 is symmetric under the rotations by 90 degrees and the reflections.
+	D8
+)
+
+// Symmetries returns all symmetries in the order they are cycled through.
+func Symmetries() []Symmetry {
+	return []Symmetry{C1, C2, C4, D4, D8}
+}
+
+// String returns the name of the symmetry.
+func (s Symmetry) String() string {
+	switch s {
+	case C1:
+		return "C1"
+	case C2:
+		return "C2"
+	case C4:
+		return "C4"
+	case D4:
+		return "D4"
+	case D8:
+		return "D8"
+	default:
+		return fmt.Sprintf("Symmetry(%d)", int(s))
+	}
+}
+
+// ParseSymmetry parses the name of the symmetry case-insensitively.
+func ParseSymmetry(name string) (Symmetry, error) {
+	for _, s := range Symmetries() {
+		if strings.EqualFold(name, s.String()) {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid symmetry %q: expected C1, C2, C4, D4 or D8", name)
+}
+
+// square reports whether the symmetry needs a square, because it maps the
+// columns to the rows.
+func (s Symmetry) square() bool {
+	return s == C4 || s == D8
+}
+
+// images returns the cells the cell is mapped to by the symmetry inside the
+// rectangle of the given size, including the cell itself.
+func (s Symmetry) images(p engine.Point, width, height int) []engine.Point {
+	x, y := p.X, p.Y
+	right, bottom := width-1, height-1
+
+	switch s {
+	case C2:
+		return []engine.Point{p, {X: right - x, Y: bottom - y}}
+	case C4:
+		return []engine.Point{p, {X: right - y, Y: x}, {X: right - x, Y: bottom - y}, {X: y, Y: bottom - x}}
+	case D4:
+		return []engine.Point{p, {X: right - x, Y: y}, {X: x, Y: bottom - y}, {X: right - x, Y: bottom - y}}
+	case D8:
+		return []engine.Point{
+			p, {X: right - y, Y: x}, {X: right - x, Y: bottom - y}, {X: y, Y: bottom - x},
+			{X: right - x, Y: y}, {X: x, Y: bottom - y}, {X: y, Y: x}, {X: right - y, Y: bottom - x},
+		}
+	default:
+		return []engine.Point{p}
+	}
+}
+
+// Rect returns the rectangle the soup fills. The symmetries that need a square
+// use the centered square of the rectangle.
+func (s Symmetry) Rect(r engine.Rect) engine.Rect {
+	if !s.square() || r.Width() == r.Height() {
+		return r
+	}
+
+	size := min(r.Width(), r.Height())
+	minX := r.MinX + (r.Width()-size)/2
+	minY := r.MinY + (r.Height()-size)/2
+
+	return engine.Rect{MinX: minX, MinY: minY, MaxX: minX + size, MaxY: minY + size}
+}
+
+// Fill sets every cell inside the rectangle to alive with the probability of
+// the density, or to dead. The same seed always gives the same soup. The
+// cells are chosen in rows, and the images of a cell under the symmetry get
+// the same state as the cell.
+func Fill(e engine.Engine, r engine.Rect, seed uint64, density float64, s Symmetry) {
+	r = s.Rect(r)
+	width, height := r.Width(), r.Height()
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	for y := range height {
+		for x := range width {
+			images := s.images(engine.Point{X: x, Y: y}, width, height)
+
+			// The state is chosen once for the first cell of the images in
+			// the order of the rows.
+			if !first(images) {
+				continue
+			}
+
+			state := cell.Dead
+			if rng.Float64() < density {
+				state = cell.Alive
+			}
+
+			for _, p := range images {
+				e.SetCell(r.MinX+p.X, r.MinY+p.Y, state)
+			}
+		}
+	}
+}
+
+// first reports whether the first cell comes first in the order of the rows
+// among the cells.
+func first(cells []engine.Point) bool {
+	for _, p := range cells[1:] {
+		if p.Y < cells[0].Y || p.Y == cells[0].Y && p.X < cells[0].X {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,110 @@
+package soup_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/soup"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+func TestFill(t *testing.T) {
+	r := engine.Rect{MinX: -3, MinY: 2, MaxX: 7, MaxY: 8}
+
+	tt := []struct {
+		name         string
+		symmetry     soup.Symmetry
+		expectedRect engine.Rect
+		// transforms map the cells relative to the top left corner of the
+		// square or the rectangle of the given size.
+		transforms []func(x, y, size int) (int, int)
+	}{
+		{
+			name:         "C1",
+			symmetry:     soup.C1,
+			expectedRect: r,
+		},
+		{
+			name:         "C2",
+			symmetry:     soup.C2,
+			expectedRect: r,
+			transforms: []func(x, y, size int) (int, int){
+				func(x, y, _ int) (int, int) { return 9 - x, 5 - y },
+			},
+		},
+		{
+			name:         "C4",
+			symmetry:     soup.C4,
+			expectedRect: engine.Rect{MinX: -1, MinY: 2, MaxX: 5, MaxY: 8},
+			transforms: []func(x, y, size int) (int, int){
+				func(x, y, size int) (int, int) { return size - 1 - y, x },
+			},
+		},
+		{
+			name:         "D4",
+			symmetry:     soup.D4,
+			expectedRect: r,
+			transforms: []func(x, y, size int) (int, int){
+				func(x, y, _ int) (int, int) { return 9 - x, y },
+				func(x, y, _ int) (int, int) { return x, 5 - y },
+			},
+		},
+		{
+			name:         "D8",
+			symmetry:     soup.D8,
+			expectedRect: engine.Rect{MinX: -1, MinY: 2, MaxX: 5, MaxY: 8},
+			transforms: []func(x, y, size int) (int, int){
+				func(x, y, size int) (int, int) { return size - 1 - y, x },
+				func(x, y, _ int) (int, int) { return y, x },
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedRect, tc.symmetry.Rect(r))
+
+			u := sparse.New()
+			soup.Fill(u, r, 42, 0.5, tc.symmetry)
+
+			alive := engine.AliveCells(u)
+			assert.NotEmpty(t, alive)
+
+			for _, p := range alive {
+				assert.True(t, tc.expectedRect.Contains(p.X, p.Y))
+
+				x, y := p.X-tc.expectedRect.MinX, p.Y-tc.expectedRect.MinY
+				for _, transform := range tc.transforms {
+					tx, ty := transform(x, y, tc.expectedRect.Width())
+					assert.Equal(t, cell.Alive, u.Cell(tc.expectedRect.MinX+tx, tc.expectedRect.MinY+ty))
+				}
+			}
+
+			// The same seed gives the same soup, and the cells inside the
+			// rectangle are replaced.
+			other := sparse.New()
+			other.SetCell(tc.expectedRect.MinX, tc.expectedRect.MinY, cell.Alive)
+			soup.Fill(other, r, 42, 0.5, tc.symmetry)
+			assert.Equal(t, alive, engine.AliveCells(other))
+		})
+	}
+}
+
+func TestParseSymmetry(t *testing.T) {
+	for _, s := range soup.Symmetries() {
+		parsed, err := soup.ParseSymmetry(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+
+	parsed, err := soup.ParseSymmetry("d8")
+	require.NoError(t, err)
+	assert.Equal(t, soup.D8, parsed)
+
+	_, err = soup.ParseSymmetry("D2")
+	assert.EqualError(t, err, `invalid symmetry "D2": expected C1, C2, C4, D4 or D8`)
+}
@@ -0,0 +1,182 @@
+package sparse
+
+import (
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+)
+
+var (
+	_ engine.Rewindable = (*Universe)(nil)
+	_ engine.Counter    = (*Universe)(nil)
+)
+
+// Universe represents an unbounded universe of cells. Only the alive cells are
+// stored, so the coordinates can be any integers, including negative ones.
+type Universe struct {
+	generation int
+	rule       rule.Rule
+	alive      map[engine.Point]struct{}
+	// births, deaths and bounds are counted while the next generation is
+	// calculated, and the bounds are updated by the edits. The bounds are
+	// found again only when an edit kills a cell on their border.
+	births      int
+	deaths      int
+	edited      bool
+	bounds      engine.Rect
+	boundsKnown bool
+}
+
+// Option configures the universe.
+type Option func(*Universe)
+
+// WithRule sets the rule used to calculate the next generation of the cells.
+// By default, Conway's rule (B3/S23) is used.
+func WithRule(r rule.Rule) Option {
+	return func(u *Universe) {
+		u.rule = r
+	}
+}
+
+// New creates a new empty universe. It panics if the rule makes the dead cells
+// without alive neighbors alive (B0), because the universe would be infinitely
+// populated.
+func New(opts ...Option) *Universe {
+	u := &Universe{
+		rule:        rule.Conway,
+		alive:       make(map[engine.Point]struct{}),
+		boundsKnown: true,
+	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	if u.rule.Born(0) {
+		panic("sparse: rules with B0 are not supported by the unbounded universe")
+	}
+
+	return u
+}
+
+// Rule returns the rule used to calculate the next generation of the cells.
+func (u *Universe) Rule() rule.Rule {
+	return u.rule
+}
+
+// Generation returns the current generation of the universe.
+func (u *Universe) Generation() int {
+	return u.generation
+}
+
+// SetGeneration sets the current generation of the universe without changing
+// the cells.
+func (u *Universe) SetGeneration(generation int) {
+	u.edited = u.edited || generation != u.generation
+	u.generation = generation
+}
+
+// Population returns the number of alive cells.
+func (u *Universe) Population() int {
+	return len(u.alive)
+}
+
+// Changes returns the number of cells born and the number of cells died in
+// the last generation. It returns false if the universe is edited since.
+func (u *Universe) Changes() (births, deaths int, ok bool) {
+	return u.births, u.deaths, !u.edited
+}
+
+// Cell returns the cell in the x-th column and y-th row.
+func (u *Universe) Cell(x, y int) *cell.Cell {
+	if _, ok := u.alive[engine.Point{X: x, Y: y}]; ok {
+		return cell.Alive
+	}
+
+	return cell.Dead
+}
+
+// SetCell sets the cell in the x-th column and y-th row.
+func (u *Universe) SetCell(x, y int, c *cell.Cell) {
+	p := engine.Point{X: x, Y: y}
+	_, alive := u.alive[p]
+
+	switch {
+	case alive == (c == cell.Alive):
+		return
+	case c == cell.Alive:
+		u.alive[p] = struct{}{}
+		u.bounds = u.bounds.Union(engine.Rect{MinX: x, MinY: y, MaxX: x + 1, MaxY: y + 1})
+	default:
+		delete(u.alive, p)
+		u.boundsKnown = u.boundsKnown && !u.bounds.Border(x, y)
+	}
+
+	u.edited = true
+}
+
+// BoundingBox returns the smallest rectangle that contains all alive cells.
+// The cells are scanned only if an edit made the last bounds unknown.
+func (u *Universe) BoundingBox() engine.Rect {
+	if u.boundsKnown {
+		return u.bounds
+	}
+
+	u.bounds = engine.Rect{}
+	for p := range u.alive {
+		u.bounds = u.bounds.Union(engine.Rect{MinX: p.X, MinY: p.Y, MaxX: p.X + 1, MaxY: p.Y + 1})
+	}
+
+	u.boundsKnown = true
+
+	return u.bounds
+}
+
+// NextGeneration moves the universe to the next generation.
+func (u *Universe) NextGeneration() {
+	// Only the alive cells and their neighbors can be alive in the next
+	// generation, so we count the alive neighbors only around them.
+	aliveNeighbors := make(map[engine.Point]int, len(u.alive)*8)
+	for p := range u.alive {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				// Skip the cell itself.
+				if dx == 0 && dy == 0 {
+					continue
+				}
+
+				aliveNeighbors[engine.Point{X: p.X + dx, Y: p.Y + dy}]++
+			}
+		}
+	}
+
+	u.births = 0
+
+	nextGeneration := make(map[engine.Point]struct{}, len(u.alive))
+	for p, n := range aliveNeighbors {
+		_, alive := u.alive[p]
+		if u.rule.Next(alive, n) {
+			nextGeneration[p] = struct{}{}
+
+			if !alive {
+				u.births++
+			}
+		}
+	}
+
+	// The alive cells without alive neighbors are not counted above.
+	if u.rule.Survives(0) {
+		for p := range u.alive {
+			if _, ok := aliveNeighbors[p]; !ok {
+				nextGeneration[p] = struct{}{}
+			}
+		}
+	}
+
+	// The cells that are not born survive from the current generation.
+	u.deaths = len(u.alive) - (len(nextGeneration) - u.births)
+	u.alive = nextGeneration
+	u.generation++
+	u.edited = false
+	u.boundsKnown = false
+}
@@ -0,0 +1,145 @@
+package sparse_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/grid"
+	"github.com/ivanlemeshev/gameoflife/internal/game/rule"
+	"github.com/ivanlemeshev/gameoflife/internal/game/sparse"
+)
+
+func TestUniverse_SetCell(t *testing.T) {
+	u := sparse.New()
+	assert.Equal(t, cell.Dead, u.Cell(-1000, 1000))
+
+	u.SetCell(-1000, 1000, cell.Alive)
+	assert.Equal(t, cell.Alive, u.Cell(-1000, 1000))
+	assert.Equal(t, 1, u.Population())
+
+	u.SetCell(-1000, 1000, cell.Dead)
+	assert.Equal(t, cell.Dead, u.Cell(-1000, 1000))
+	assert.Equal(t, 0, u.Population())
+}
+
+func TestUniverse_Changes(t *testing.T) {
+	u := sparse.New()
+	for x := 1; x <= 3; x++ {
+		u.SetCell(x, 2, cell.Alive)
+	}
+
+	// The blinker turns: two cells are born and two cells die.
+	u.NextGeneration()
+	births, deaths, ok := u.Changes()
+	assert.True(t, ok)
+	assert.Equal(t, 2, births)
+	assert.Equal(t, 2, deaths)
+
+	// The changes are not known after an edit.
+	u.SetCell(10, 10, cell.Alive)
+	_, _, ok = u.Changes()
+	assert.False(t, ok)
+
+	// The lonely cells die.
+	u.NextGeneration()
+	births, deaths, ok = u.Changes()
+	assert.True(t, ok)
+	assert.Equal(t, 2, births)
+	assert.Equal(t, 3, deaths)
+}
+
+func TestUniverse_BoundingBox(t *testing.T) {
+	u := sparse.New()
+	assert.True(t, u.BoundingBox().Empty())
+
+	u.SetCell(-3, 2, cell.Alive)
+	u.SetCell(4, -5, cell.Alive)
+	assert.Equal(t, engine.Rect{MinX: -3, MinY: -5, MaxX: 5, MaxY: 3}, u.BoundingBox())
+
+	// The bounds shrink when a cell on their border dies.
+	u.SetCell(4, -5, cell.Dead)
+	assert.Equal(t, engine.Rect{MinX: -3, MinY: 2, MaxX: -2, MaxY: 3}, u.BoundingBox())
+}
+
+func TestUniverse_NextGeneration(t *testing.T) {
+	tt := []struct {
+		name  string
+		rule  rule.Rule
+		cells []engine.Point
+	}{
+		{
+			name:  "the R-pentomino in Conway's rule",
+			rule:  rule.Conway,
+			cells: []engine.Point{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+		},
+		{
+			name:  "the replicator in HighLife",
+			rule:  rule.HighLife,
+			cells: []engine.Point{{X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 1, Y: 1}, {X: 4, Y: 1}, {X: 0, Y: 2}, {X: 4, Y: 2}, {X: 0, Y: 3}, {X: 3, Y: 3}, {X: 0, Y: 4}, {X: 1, Y: 4}, {X: 2, Y: 4}},
+		},
+		{
+			name:  "the seeds in Seeds",
+			rule:  rule.Seeds,
+			cells: []engine.Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 3, Y: 1}},
+		},
+	}
+
+	// The pattern is placed in the middle of the grid, so it does not reach
+	// the edges of the grid in the given number of generations.
+	size := 100
+	offset := size / 2
+	generations := 30
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			u := sparse.New(sparse.WithRule(tc.rule))
+			sg := grid.New(size, size, grid.WithRule(tc.rule))
+
+			for _, p := range tc.cells {
+				u.SetCell(p.X, p.Y, cell.Alive)
+				sg.SetCell(p.X+offset, p.Y+offset, cell.Alive)
+			}
+
+			for range generations {
+				u.NextGeneration()
+				sg.NextGeneration()
+
+				expected := engine.AliveCells(sg)
+				for i := range expected {
+					expected[i].X -= offset
+					expected[i].Y -= offset
+				}
+
+				assert.Equal(t, expected, engine.AliveCells(u))
+			}
+
+			assert.Equal(t, generations, u.Generation())
+		})
+	}
+}
+
+func TestUniverse_GliderIsNotClipped(t *testing.T) {
+	// The glider moves one cell up and one cell left every four generations.
+	u := sparse.New()
+	u.SetCell(0, 0, cell.Alive)
+	u.SetCell(1, 0, cell.Alive)
+	u.SetCell(2, 0, cell.Alive)
+	u.SetCell(0, 1, cell.Alive)
+	u.SetCell(1, 2, cell.Alive)
+
+	for range 4 * 100 {
+		u.NextGeneration()
+	}
+
+	assert.Equal(t, 5, u.Population())
+	assert.Equal(t, engine.Rect{MinX: -100, MinY: -100, MaxX: -97, MaxY: -97}, u.BoundingBox())
+}
+
+func TestNew_PanicsWithB0Rule(t *testing.T) {
+	assert.Panics(t, func() {
+		sparse.New(sparse.WithRule(rule.MustParse("B03/S23")))
+	})
+}
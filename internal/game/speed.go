@@ -0,0 +1,96 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+const (
+	// defaultSkip is the number of generations skipped at once by default.
+	defaultSkip = 100
+	// maxSkip is the largest number of generations skipped at once by the
+	// engines that advance many generations at once.
+	maxSkip = 1_000_000
+	// maxSteppedSkip is the largest number of generations skipped at once by
+	// the other engines. They calculate every generation while the game does
+	// not respond, so the skip is kept short.
+	maxSteppedSkip = 1000
+)
+
+// speeds are the intervals between the generations the speed is changed
+// through, from the slowest to the fastest.
+var speeds = []time.Duration{
+	2 * time.Second,
+	time.Second,
+	500 * time.Millisecond,
+	250 * time.Millisecond,
+	100 * time.Millisecond,
+	50 * time.Millisecond,
+	20 * time.Millisecond,
+	10 * time.Millisecond,
+}
+
+// faster returns the longest interval of the speeds that is shorter than the
+// given one. The interval is kept if there is no such speed.
+func faster(interval time.Duration) time.Duration {
+	for _, speed := range speeds {
+		if speed < interval {
+			return speed
+		}
+	}
+
+	return interval
+}
+
+// slower returns the shortest interval of the speeds that is longer than the
+// given one. The interval is kept if there is no such speed.
+func slower(interval time.Duration) time.Duration {
+	for i := len(speeds) - 1; i >= 0; i-- {
+		if speeds[i] > interval {
+			return speeds[i]
+		}
+	}
+
+	return interval
+}
+
+// step moves the cells one generation forward.
+func (g *Game) step() {
+	g.universe.NextGeneration()
+	g.recordPopulation()
+	g.record()
+}
+
+// skipGenerations moves the cells the number of generations to skip forward
+// without rendering the generations in between.
+func (g *Game) skipGenerations() {
+	skip := min(g.skip, g.maxSkip())
+
+	start := time.Now()
+	engine.Advance(g.universe, skip)
+	g.recordPopulation()
+	g.record()
+
+	g.status = fmt.Sprintf("Skipped %d generations in %s.", skip, time.Since(start).Round(time.Millisecond))
+}
+
+// maxSkip returns the largest number of generations the engine skips at once.
+func (g *Game) maxSkip() int {
+	if _, ok := g.universe.(engine.Advancer); ok {
+		return maxSkip
+	}
+
+	return maxSteppedSkip
+}
+
+// increaseSkip multiplies the number of generations to skip by ten.
+func (g *Game) increaseSkip() {
+	g.skip = min(g.skip*10, g.maxSkip())
+}
+
+// decreaseSkip divides the number of generations to skip by ten.
+func (g *Game) decreaseSkip() {
+	g.skip = max(g.skip/10, 1)
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+)
+
+const (
+	// statsPanelWidth is the number of terminal columns taken by the
+	// statistics panel, including the margin before it.
+	statsPanelWidth = 24
+	// statsMargin is the number of columns between the cells and the panel.
+	statsMargin = 2
+	// populationHistoryLimit is the number of the latest populations shown
+	// in the sparkline, one per column.
+	populationHistoryLimit = statsPanelWidth - statsMargin
+)
+
+// sparks are the bars of the sparkline from the lowest to the highest.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// toggleStats shows or hides the statistics panel next to the cells. The
+// cells take the rest of the terminal, so the viewport is resized keeping
+// the cell in its center.
+func (g *Game) toggleStats() {
+	g.showStats = !g.showStats
+
+	if g.terminalWidth == 0 || g.terminalHeight == 0 {
+		return
+	}
+
+	// The cells on a resized torus evolve differently.
+	if g.fitTerminal {
+		g.resize()
+		g.detector.Reset()
+	}
+
+	center := g.viewport.Center()
+	g.viewport.Resize(g.terminalCapacity())
+	g.viewport.CenterOn(center)
+}
+
+// statsWidth returns the number of terminal columns taken by the statistics
+// panel.
+func (g *Game) statsWidth() int {
+	if !g.showStats {
+		return 0
+	}
+
+	return statsPanelWidth
+}
+
+// recordPopulation adds the current population to the sparkline. It is
+// called once per calculated generation or skip.
+func (g *Game) recordPopulation() {
+	g.populations = append(g.populations, engine.Population(g.universe))
+	if len(g.populations) > populationHistoryLimit {
+		g.populations = g.populations[len(g.populations)-populationHistoryLimit:]
+	}
+}
+
+// renderStats returns the statistics panel. The engines keep the population
+// and the bounds of the cells, so they are not counted on every render. The
+// births and the deaths are known only for the engines that count them while
+// calculating the generations, and only until the cells are edited.
+func (g *Game) renderStats() string {
+	population := engine.Population(g.universe)
+	bounds := g.universe.BoundingBox()
+
+	births, deaths := "-", "-"
+	if counter, ok := g.universe.(engine.Counter); ok {
+		if b, d, ok := counter.Changes(); ok {
+			births, deaths = fmt.Sprint(b), fmt.Sprint(d)
+		}
+	}
+
+	density := 0.0
+	if area := bounds.Width() * bounds.Height(); area > 0 {
+		density = float64(population) / float64(area) * 100
+	}
+
+	lines := []string{
+		"Statistics",
+		fmt.Sprintf("Population: %d", population),
+		"Births:     " + births,
+		"Deaths:     " + deaths,
+		fmt.Sprintf("Bounds:     %dx%d", bounds.Width(), bounds.Height()),
+		fmt.Sprintf("Density:    %.1f%%", density),
+		"",
+		"Population history:",
+		sparkline(g.populations),
+	}
+
+	if len(g.populations) > 0 {
+		lines = append(lines, fmt.Sprintf("%d to %d", slices.Min(g.populations), slices.Max(g.populations)))
+	}
+
+	return lipgloss.NewStyle().
+		MarginLeft(statsMargin).
+		Width(statsPanelWidth - statsMargin).
+		Render(strings.Join(lines, "\n"))
+}
+
+// sparkline draws the values as bars scaled between the lowest and the
+// highest of them.
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	lowest, highest := slices.Min(values), slices.Max(values)
+
+	var sb strings.Builder
+
+	for _, v := range values {
+		level := 0
+		if highest > lowest {
+			level = (v - lowest) * (len(sparks) - 1) / (highest - lowest)
+		}
+
+		sb.WriteRune(sparks[level])
+	}
+
+	return sb.String()
+}
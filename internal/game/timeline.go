@@ -0,0 +1,100 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// keyframeInterval is the number of generations between the keyframes of
+	// the timeline.
+	keyframeInterval = 50
+	// keyframeLimit is the number of the latest keyframes of the timeline.
+	keyframeLimit = 1000
+)
+
+// renderTimeline renders the recorded generations as a bar with the current
+// generation marked, followed by the range of the recorded generations.
+func (g *Game) renderTimeline(sb *strings.Builder) {
+	width := g.timelineWidth()
+
+	bar := []rune(strings.Repeat("─", width))
+	if x, ok := g.timelineColumn(width); ok {
+		bar[x] = '●'
+	}
+
+	sb.WriteString(string(bar))
+	sb.WriteString(g.timelineLabel())
+	sb.WriteString("\n")
+}
+
+// timelineLabel returns the range of the recorded generations.
+func (g *Game) timelineLabel() string {
+	if g.timeline.Empty() {
+		return " -"
+	}
+
+	return fmt.Sprintf(" %d-%d", g.timeline.First(), g.timeline.Last())
+}
+
+// timelineWidth returns the number of terminal columns taken by the bar of
+// the timeline. The bar and the label take the width of the board.
+func (g *Game) timelineWidth() int {
+	return max(g.layout().width()-utf8.RuneCountInString(g.timelineLabel()), 1)
+}
+
+// timelineColumn returns the column of the bar of the given width that marks
+// the current generation. It returns false if the generation is not recorded.
+func (g *Game) timelineColumn(width int) (int, bool) {
+	generation := g.universe.Generation()
+	if g.timeline.Empty() || generation < g.timeline.First() || generation > g.timeline.Last() {
+		return 0, false
+	}
+
+	span := g.timeline.Last() - g.timeline.First()
+	if span == 0 {
+		return 0, true
+	}
+
+	return (generation - g.timeline.First()) * (width - 1) / span, true
+}
+
+// timelineGeneration returns the recorded generation marked by the column of
+// the bar of the timeline. It returns false if there is no bar there.
+func (g *Game) timelineGeneration(x int) (int, bool) {
+	width := g.timelineWidth()
+	if g.timeline.Empty() || x < 0 || x >= width {
+		return 0, false
+	}
+
+	if width == 1 {
+		return g.timeline.First(), true
+	}
+
+	span := g.timeline.Last() - g.timeline.First()
+
+	return g.timeline.First() + (x*span+(width-1)/2)/(width-1), true
+}
+
+// seek moves the cells to the recorded generation. The generation is limited
+// to the recorded ones.
+func (g *Game) seek(generation int) {
+	g.pause()
+	g.status = ""
+
+	if g.timeline.Empty() {
+		g.status = "No generation is recorded."
+		return
+	}
+
+	generation = min(max(generation, g.timeline.First()), g.timeline.Last())
+	if !g.timeline.Seek(g.universe, generation) {
+		g.status = fmt.Sprintf("Failed to seek to the generation %d.", generation)
+		return
+	}
+
+	// Seeking can be undone like the other changes of the cells.
+	g.history.Record(g.universe)
+	g.detector.Reset()
+}
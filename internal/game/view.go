@@ -0,0 +1,122 @@
+package game
+
+import (
+	"strings"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/cell"
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/render"
+)
+
+// renderCells renders the screen cells of the viewport with the current
+// renderer. A screen cell is alive if it shows at least one alive cell, and
+// the screen cells outside a bounded engine are outside. The screen cells that
+// show the cell under the keyboard cursor, the ghost of the pasted clipboard
+// and the selected cells are marked.
+func (g *Game) renderCells(sb *strings.Builder) {
+	for _, line := range g.renderer.Render(g.pixels()) {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+}
+
+// renderHelp renders the key bindings in place of the cells, a group of them
+// per line cut to the terminal width.
+func (g *Game) renderHelp(sb *strings.Builder) {
+	for _, group := range g.keys.FullHelp() {
+		sb.WriteString(g.help.ShortHelpView(group))
+		sb.WriteString("\n")
+	}
+}
+
+// pixels returns the states of the screen cells of the viewport.
+func (g *Game) pixels() [][]render.Pixel {
+	v := g.viewport
+	alive := g.aliveScreenCells()
+	ghost := g.ghostScreenCells()
+	board, bounded := g.board()
+
+	var selected engine.Rect
+	if g.selection != nil {
+		selected = g.selection.rect()
+	}
+
+	pixels := make([][]render.Pixel, v.Height)
+	for y := range pixels {
+		pixels[y] = make([]render.Pixel, v.Width)
+
+		for x := range pixels[y] {
+			block := v.Block(x, y)
+
+			switch {
+			case bounded && board.Intersect(block).Empty():
+				pixels[y][x] = render.Outside
+			case g.cursor != nil && block.Contains(g.cursor.position.X, g.cursor.position.Y) && alive[y][x]:
+				pixels[y][x] = render.CursorAlive
+			case g.cursor != nil && block.Contains(g.cursor.position.X, g.cursor.position.Y):
+				pixels[y][x] = render.CursorDead
+			case ghost[y][x]:
+				pixels[y][x] = render.Ghost
+			case !selected.Intersect(block).Empty() && alive[y][x]:
+				pixels[y][x] = render.SelectedAlive
+			case !selected.Intersect(block).Empty():
+				pixels[y][x] = render.SelectedDead
+			case alive[y][x]:
+				pixels[y][x] = render.Alive
+			default:
+				pixels[y][x] = render.Dead
+			}
+		}
+	}
+
+	return pixels
+}
+
+// aliveScreenCells returns which screen cells show at least one alive cell.
+func (g *Game) aliveScreenCells() [][]bool {
+	v := g.viewport
+
+	alive := make([][]bool, v.Height)
+	for y := range alive {
+		alive[y] = make([]bool, v.Width)
+	}
+
+	if v.Zoom >= 0 {
+		for y := range alive {
+			for x := range alive[y] {
+				p := v.Cell(x, y)
+				alive[y][x] = g.universe.Cell(p.X, p.Y) == cell.Alive
+			}
+		}
+
+		return alive
+	}
+
+	// A screen cell shows many cells, so only the visible cells inside the
+	// bounding box are checked.
+	visible := v.Bounds().Intersect(g.universe.BoundingBox())
+	for y := visible.MinY; y < visible.MaxY; y++ {
+		for x := visible.MinX; x < visible.MaxX; x++ {
+			if g.universe.Cell(x, y) == cell.Dead {
+				continue
+			}
+
+			if sx, sy, ok := v.ScreenCell(engine.Point{X: x, Y: y}); ok {
+				alive[sy][sx] = true
+			}
+		}
+	}
+
+	return alive
+}
+
+// board returns the cells of the bounded engine. It returns false if the
+// engine is unbounded.
+func (g *Game) board() (engine.Rect, bool) {
+	bounded, ok := g.universe.(engine.Bounded)
+	if !ok {
+		return engine.Rect{}, false
+	}
+
+	return engine.Rect{MaxX: bounded.Width(), MaxY: bounded.Height()}, true
+}
@@ -0,0 +1,189 @@
+package viewport
+
+import "github.com/ivanlemeshev/gameoflife/internal/game/engine"
+
+const (
+	// MinZoom is the smallest zoom level, a screen cell shows 64x64 cells.
+	MinZoom = -6
+	// MaxZoom is the largest zoom level, a cell takes 8x8 screen cells.
+	MaxZoom = 3
+)
+
+// Viewport is the visible part of the universe. The screen is split into
+// screen cells, and the zoom level defines how many cells of the universe
+// are shown by a screen cell.
+type Viewport struct {
+	// X is the column of the cell in the top left corner.
+	X int
+	// Y is the row of the cell in the top left corner.
+	Y int
+	// Zoom is the zoom level. If it is positive, a cell takes 2^Zoom x 2^Zoom
+	// screen cells. If it is negative, a screen cell shows 2^-Zoom x 2^-Zoom
+	// cells. At zero, a cell takes one screen cell.
+	Zoom int
+	// Width is the number of columns of screen cells.
+	Width int
+	// Height is the number of rows of screen cells.
+	Height int
+}
+
+// New creates a viewport with the given number of columns and rows of screen
+// cells. The origin is in the top left corner.
+func New(width, height int) Viewport {
+	return Viewport{Width: width, Height: height}
+}
+
+// Cell returns the cell shown in the x-th column and y-th row of screen
+// cells. If a screen cell shows several cells, the top left one is returned.
+func (v Viewport) Cell(x, y int) engine.Point {
+	if v.Zoom >= 0 {
+		return engine.Point{X: v.X + x>>v.Zoom, Y: v.Y + y>>v.Zoom}
+	}
+
+	return engine.Point{X: v.X + x<<-v.Zoom, Y: v.Y + y<<-v.Zoom}
+}
+
+// Block returns the cells shown in the x-th column and y-th row of screen cells.
+func (v Viewport) Block(x, y int) engine.Rect {
+	p := v.Cell(x, y)
+	size := v.cellsPerScreenCell()
+
+	return engine.Rect{MinX: p.X, MinY: p.Y, MaxX: p.X + size, MaxY: p.Y + size}
+}
+
+// ScreenCell returns the column and row of the screen cell that shows the
+// cell. It returns false if the cell is not visible.
+func (v Viewport) ScreenCell(p engine.Point) (int, int, bool) {
+	if !v.Bounds().Contains(p.X, p.Y) {
+		return 0, 0, false
+	}
+
+	if v.Zoom >= 0 {
+		return (p.X - v.X) << v.Zoom, (p.Y - v.Y) << v.Zoom, true
+	}
+
+	return (p.X - v.X) >> -v.Zoom, (p.Y - v.Y) >> -v.Zoom, true
+}
+
+// Bounds returns the visible cells, including the partially visible ones.
+func (v Viewport) Bounds() engine.Rect {
+	return engine.Rect{
+		MinX: v.X,
+		MinY: v.Y,
+		MaxX: v.X + v.cells(v.Width, true),
+		MaxY: v.Y + v.cells(v.Height, true),
+	}
+}
+
+// Pan moves the viewport by the given number of steps. A step is a screen
+// cell, or a cell if it takes several screen cells.
+func (v *Viewport) Pan(dx, dy int) {
+	step := v.cellsPerScreenCell()
+
+	v.X += dx * step
+	v.Y += dy * step
+}
+
+// Scroll moves the viewport by the given number of screen cells. If a cell
+// takes several screen cells, the movement is rounded towards zero.
+func (v *Viewport) Scroll(dx, dy int) {
+	if v.Zoom >= 0 {
+		v.X += dx / (1 << v.Zoom)
+		v.Y += dy / (1 << v.Zoom)
+
+		return
+	}
+
+	v.X += dx << -v.Zoom
+	v.Y += dy << -v.Zoom
+}
+
+// ZoomIn increases the zoom level keeping the cell in the center of the
+// viewport. It returns false if the zoom level is already the largest.
+func (v *Viewport) ZoomIn() bool {
+	return v.SetZoom(v.Zoom + 1)
+}
+
+// ZoomOut decreases the zoom level keeping the cell in the center of the
+// viewport. It returns false if the zoom level is already the smallest.
+func (v *Viewport) ZoomOut() bool {
+	return v.SetZoom(v.Zoom - 1)
+}
+
+// SetZoom sets the zoom level keeping the cell in the center of the
+// viewport. It returns false if the zoom level is out of range.
+func (v *Viewport) SetZoom(zoom int) bool {
+	if zoom < MinZoom || zoom > MaxZoom {
+		return false
+	}
+
+	center := v.Center()
+	v.Zoom = zoom
+	v.CenterOn(center)
+
+	return true
+}
+
+// Center returns the cell in the center of the viewport.
+func (v Viewport) Center() engine.Point {
+	return engine.Point{
+		X: v.X + v.cells(v.Width, false)/2,
+		Y: v.Y + v.cells(v.Height, false)/2,
+	}
+}
+
+// CenterOn moves the viewport so the cell is in its center.
+func (v *Viewport) CenterOn(p engine.Point) {
+	v.X = p.X - v.cells(v.Width, false)/2
+	v.Y = p.Y - v.cells(v.Height, false)/2
+}
+
+// Fit sets the largest zoom level at which all cells of the rectangle are
+// visible, and centers the viewport on the rectangle. If the rectangle is
+// too large, the smallest zoom level is used. The empty rectangle is ignored.
+func (v *Viewport) Fit(r engine.Rect) {
+	if r.Empty() {
+		return
+	}
+
+	for v.Zoom = MaxZoom; v.Zoom > MinZoom; v.Zoom-- {
+		if v.cells(v.Width, false) >= r.Width() && v.cells(v.Height, false) >= r.Height() {
+			break
+		}
+	}
+
+	v.X = r.MinX - (v.cells(v.Width, false)-r.Width())/2
+	v.Y = r.MinY - (v.cells(v.Height, false)-r.Height())/2
+}
+
+// Resize changes the number of columns and rows of screen cells keeping the
+// cell in the top left corner.
+func (v *Viewport) Resize(width, height int) {
+	v.Width = width
+	v.Height = height
+}
+
+// cellsPerScreenCell returns the number of cells shown by a screen cell in a
+// row. It is one if a cell takes several screen cells.
+func (v Viewport) cellsPerScreenCell() int {
+	if v.Zoom >= 0 {
+		return 1
+	}
+
+	return 1 << -v.Zoom
+}
+
+// cells returns the number of cells shown by the given number of screen
+// cells in a row. The partially visible cell is counted if it is requested.
+func (v Viewport) cells(screenCells int, partial bool) int {
+	if v.Zoom < 0 {
+		return screenCells << -v.Zoom
+	}
+
+	size := 1 << v.Zoom
+	if partial {
+		return (screenCells + size - 1) / size
+	}
+
+	return screenCells / size
+}
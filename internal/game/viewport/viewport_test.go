@@ -0,0 +1,183 @@
+package viewport_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ivanlemeshev/gameoflife/internal/game/engine"
+	"github.com/ivanlemeshev/gameoflife/internal/game/viewport"
+)
+
+func TestViewport_Cell(t *testing.T) {
+	tt := []struct {
+		name          string
+		zoom          int
+		x             int
+		y             int
+		expectedCell  engine.Point
+		expectedBlock engine.Rect
+	}{
+		{
+			name:          "a cell takes a screen cell",
+			zoom:          0,
+			x:             3,
+			y:             2,
+			expectedCell:  engine.Point{X: 13, Y: -3},
+			expectedBlock: engine.Rect{MinX: 13, MinY: -3, MaxX: 14, MaxY: -2},
+		},
+		{
+			name:          "a cell takes 2x2 screen cells",
+			zoom:          1,
+			x:             3,
+			y:             2,
+			expectedCell:  engine.Point{X: 11, Y: -4},
+			expectedBlock: engine.Rect{MinX: 11, MinY: -4, MaxX: 12, MaxY: -3},
+		},
+		{
+			name:          "a screen cell shows 4x4 cells",
+			zoom:          -2,
+			x:             3,
+			y:             2,
+			expectedCell:  engine.Point{X: 22, Y: 3},
+			expectedBlock: engine.Rect{MinX: 22, MinY: 3, MaxX: 26, MaxY: 7},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := viewport.Viewport{X: 10, Y: -5, Zoom: tc.zoom, Width: 8, Height: 4}
+			assert.Equal(t, tc.expectedCell, v.Cell(tc.x, tc.y))
+			assert.Equal(t, tc.expectedBlock, v.Block(tc.x, tc.y))
+
+			x, y, ok := v.ScreenCell(tc.expectedCell)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCell, v.Cell(x, y))
+		})
+	}
+}
+
+func TestViewport_ScreenCell(t *testing.T) {
+	v := viewport.Viewport{X: 10, Y: -5, Zoom: 1, Width: 8, Height: 4}
+
+	x, y, ok := v.ScreenCell(engine.Point{X: 13, Y: -4})
+	assert.True(t, ok)
+	assert.Equal(t, 6, x)
+	assert.Equal(t, 2, y)
+
+	_, _, ok = v.ScreenCell(engine.Point{X: 14, Y: -4})
+	assert.False(t, ok)
+}
+
+func TestViewport_Bounds(t *testing.T) {
+	tt := []struct {
+		name     string
+		zoom     int
+		expected engine.Rect
+	}{
+		{
+			name:     "zoom level 0",
+			zoom:     0,
+			expected: engine.Rect{MinX: 1, MinY: 2, MaxX: 6, MaxY: 5},
+		},
+		{
+			name:     "zoom in with a partially visible cell",
+			zoom:     1,
+			expected: engine.Rect{MinX: 1, MinY: 2, MaxX: 4, MaxY: 4},
+		},
+		{
+			name:     "zoom out",
+			zoom:     -1,
+			expected: engine.Rect{MinX: 1, MinY: 2, MaxX: 11, MaxY: 8},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := viewport.Viewport{X: 1, Y: 2, Zoom: tc.zoom, Width: 5, Height: 3}
+			assert.Equal(t, tc.expected, v.Bounds())
+		})
+	}
+}
+
+func TestViewport_PanAndScroll(t *testing.T) {
+	v := viewport.New(10, 10)
+	v.Pan(2, -1)
+	assert.Equal(t, engine.Point{X: 2, Y: -1}, v.Cell(0, 0))
+
+	// When zoomed out, a step is the cells shown by a screen cell.
+	v.Zoom = -2
+	v.Pan(1, 1)
+	assert.Equal(t, engine.Point{X: 6, Y: 3}, v.Cell(0, 0))
+
+	v.Scroll(-1, 0)
+	assert.Equal(t, engine.Point{X: 2, Y: 3}, v.Cell(0, 0))
+
+	// When zoomed in, a step is a cell, and scrolling is rounded towards zero.
+	v.Zoom = 2
+	v.Pan(1, 0)
+	assert.Equal(t, engine.Point{X: 3, Y: 3}, v.Cell(0, 0))
+
+	v.Scroll(-5, 3)
+	assert.Equal(t, engine.Point{X: 2, Y: 3}, v.Cell(0, 0))
+}
+
+func TestViewport_Zoom(t *testing.T) {
+	v := viewport.New(20, 10)
+	v.CenterOn(engine.Point{X: 100, Y: -100})
+	assert.Equal(t, engine.Point{X: 100, Y: -100}, v.Center())
+
+	for v.ZoomIn() {
+		assert.Equal(t, engine.Point{X: 100, Y: -100}, v.Center())
+	}
+
+	assert.Equal(t, viewport.MaxZoom, v.Zoom)
+
+	for v.ZoomOut() {
+		assert.Equal(t, engine.Point{X: 100, Y: -100}, v.Center())
+	}
+
+	assert.Equal(t, viewport.MinZoom, v.Zoom)
+	assert.False(t, v.SetZoom(viewport.MinZoom-1))
+}
+
+func TestViewport_Fit(t *testing.T) {
+	tt := []struct {
+		name         string
+		rect         engine.Rect
+		expectedZoom int
+	}{
+		{
+			name:         "small rectangle is zoomed in",
+			rect:         engine.Rect{MinX: -1, MinY: -1, MaxX: 2, MaxY: 2},
+			expectedZoom: 1,
+		},
+		{
+			name:         "rectangle fits the viewport",
+			rect:         engine.Rect{MinX: 0, MinY: 0, MaxX: 20, MaxY: 10},
+			expectedZoom: 0,
+		},
+		{
+			name:         "large rectangle is zoomed out",
+			rect:         engine.Rect{MinX: -1000, MinY: 0, MaxX: 100, MaxY: 50},
+			expectedZoom: -6,
+		},
+		{
+			name:         "tall rectangle is zoomed out",
+			rect:         engine.Rect{MinX: 0, MinY: 0, MaxX: 5, MaxY: 30},
+			expectedZoom: -2,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := viewport.New(20, 10)
+			v.Fit(tc.rect)
+			assert.Equal(t, tc.expectedZoom, v.Zoom)
+
+			// The whole rectangle is visible.
+			bounds := v.Bounds()
+			assert.Equal(t, bounds, bounds.Union(tc.rect))
+		})
+	}
+}